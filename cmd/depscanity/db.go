@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"depscanity/internal/scanners/trivy"
+)
+
+// runDB handles "depscanity db import", which installs trivy DB archives into the trivy
+// cache so that scans can run with --skip-db-update / --offline-scan. It returns the exit code.
+func runDB(args []string) int {
+	if len(args) == 0 || args[0] != "import" {
+		fmt.Fprintf(os.Stderr, "Unknown db command: %q (expected import)\n", strings.Join(args, " "))
+		printUsage()
+		return 1
+	}
+
+	importCmd := flag.NewFlagSet("db import", flag.ExitOnError)
+	cacheDir := importCmd.String("cache-dir", "", "Trivy cache directory (default: trivy's own)")
+	javaDB := importCmd.String("java-db", "", "Trivy Java DB archive (javadb.tar.gz) to install")
+	if err := importCmd.Parse(args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+	if importCmd.NArg() > 1 || (importCmd.NArg() == 0 && *javaDB == "") {
+		fmt.Fprintf(os.Stderr, "Expected one trivy DB archive (db.tar.gz) and/or --java-db\n")
+		printUsage()
+		return 1
+	}
+
+	if importCmd.NArg() == 1 {
+		if err := importDB(importCmd.Arg(0), *cacheDir, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Import failed: %v\n", err)
+			return 1
+		}
+	}
+	if *javaDB != "" {
+		if err := importDB(*javaDB, *cacheDir, true); err != nil {
+			fmt.Fprintf(os.Stderr, "Import failed: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+func importDB(archive, cacheDir string, java bool) error {
+	info, err := trivy.ImportDB(archive, cacheDir, java)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Installed %s v%d (updated %s) into %s\n", info.Name, info.Version, info.UpdatedAt, info.Path)
+	return nil
+}
@@ -1,13 +1,12 @@
 package main
 
 import (
-	"bufio"
 	"context"
-	depExec "depscanity/internal/exec"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -16,20 +15,52 @@ import (
 	"depscanity/internal/detect"
 	"depscanity/internal/model"
 	"depscanity/internal/report"
-	"depscanity/internal/scanners/bun"
-	"depscanity/internal/scanners/dotnet"
-	"depscanity/internal/scanners/npm"
+	"depscanity/internal/scanners"
+	_ "depscanity/internal/scanners/builtin"
 	"depscanity/internal/scanners/trivy"
 )
 
 type Config struct {
-	OutDir      string
-	FailOn      string
-	TimeoutSec  int
-	NoOSV       bool
-	NoContainer bool
-	Image       string
-	DockerBuild bool
+	OutDir     string
+	FailOn     string
+	TimeoutSec int
+	// Phase budgets in seconds (0 = bounded only by TimeoutSec)
+	TimeoutRestore int
+	TimeoutAudit   int
+	TimeoutBuild   int
+	// TimeoutScanner is a per-target budget by scanner name, e.g. "npm=120,dotnet=600"
+	TimeoutScanner string
+	NoOSV          bool
+	AdvisoryDB     string
+	RustsecDB      string
+	RubyDB         string
+	NoContainer    bool
+	Images         stringList
+	ImagesFile     string
+	DockerBuild    bool
+	ImageArchive   string
+	OCILayout      string
+	BaseImages     bool
+	TrivyFS        bool
+	TrivyConfig    bool
+	// Build options for --docker-build; BuildArgs holds repeated KEY=VALUE flags
+	Dockerfile   string
+	BuildContext string
+	BuildTarget  string
+	Platform     string
+	BuildArgs    stringList
+	// ComposeProfiles is a comma-separated list of compose profiles to activate
+	ComposeProfiles string
+	ComposeBuild    bool
+	// Trivy cache directory; SkipDBUpdate and OfflineScan scan with the DB already in it
+	TrivyCacheDir string
+	SkipDBUpdate  bool
+	OfflineScan   bool
+	Parallel      int
+	// NugetHealth adds deprecated/outdated NuGet findings; FailOnHealth lets package health
+	// findings (deprecated, outdated, abandoned, yanked) trip --fail-on
+	NugetHealth  bool
+	FailOnHealth bool
 }
 
 func main() {
@@ -39,6 +70,9 @@ func main() {
 	}
 
 	command := os.Args[1]
+	if command == "db" {
+		os.Exit(runDB(os.Args[2:]))
+	}
 	if command != "scan" {
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
 		printUsage()
@@ -52,10 +86,36 @@ func main() {
 	scanCmd.StringVar(&config.OutDir, "out", "depscanity_out", "Output directory")
 	scanCmd.StringVar(&config.FailOn, "fail-on", "high", "Fail on severity (low, medium, high, critical)")
 	scanCmd.IntVar(&config.TimeoutSec, "timeout", 600, "Timeout in seconds")
+	scanCmd.IntVar(&config.TimeoutRestore, "timeout-restore", 0, "Timeout in seconds for each restore phase (npm ci, dotnet restore)")
+	scanCmd.IntVar(&config.TimeoutAudit, "timeout-audit", 0, "Timeout in seconds for each audit phase")
+	scanCmd.IntVar(&config.TimeoutBuild, "timeout-build", 0, "Timeout in seconds for each docker build")
+	scanCmd.StringVar(&config.TimeoutScanner, "timeout-scanner", "", "Per-target timeouts by scanner name (e.g. npm=120,dotnet=600)")
 	scanCmd.BoolVar(&config.NoOSV, "no-osv", false, "Disable OSV scanner")
+	scanCmd.StringVar(&config.AdvisoryDB, "advisory-db", "", "Local OSV database dump (directory or zip) for offline matching")
+	scanCmd.StringVar(&config.RustsecDB, "rustsec-db", "", "Local RustSec advisory-db checkout for cargo audit (offline)")
+	scanCmd.StringVar(&config.RubyDB, "ruby-advisory-db", "", "Local ruby-advisory-db checkout for bundle-audit")
 	scanCmd.BoolVar(&config.NoContainer, "no-container", false, "Disable container scanning")
-	scanCmd.StringVar(&config.Image, "image", "", "Docker image to scan directly")
+	scanCmd.Var(&config.Images, "image", "Docker image to scan directly (repeatable)")
+	scanCmd.StringVar(&config.ImagesFile, "images-file", "", "File with image references to scan, one per line")
 	scanCmd.BoolVar(&config.DockerBuild, "docker-build", false, "Build docker image before scanning")
+	scanCmd.StringVar(&config.Dockerfile, "dockerfile", "", "Dockerfile to build (implies --docker-build; default: every detected Dockerfile)")
+	scanCmd.StringVar(&config.BuildContext, "build-context", "", "Build context directory (default: the Dockerfile's directory)")
+	scanCmd.StringVar(&config.BuildTarget, "build-target", "", "Target stage to build")
+	scanCmd.StringVar(&config.Platform, "platform", "", "Platform to build for (e.g. linux/amd64)")
+	scanCmd.Var(&config.BuildArgs, "build-arg", "Build argument KEY=VALUE (repeatable)")
+	scanCmd.StringVar(&config.ImageArchive, "image-archive", "", "Image tarball (docker save or OCI) to scan without a Docker daemon")
+	scanCmd.StringVar(&config.OCILayout, "oci-layout", "", "OCI image layout directory to scan without a Docker daemon")
+	scanCmd.BoolVar(&config.BaseImages, "base-images", false, "Scan the base images of Dockerfile FROM lines without building")
+	scanCmd.BoolVar(&config.TrivyFS, "trivy-fs", false, "Run trivy fs for lockfiles without a native scanner")
+	scanCmd.BoolVar(&config.TrivyConfig, "trivy-config", false, "Run trivy config for IaC misconfigurations")
+	scanCmd.StringVar(&config.ComposeProfiles, "compose-profile", "", "Compose profiles to activate, comma-separated (default: $COMPOSE_PROFILES)")
+	scanCmd.BoolVar(&config.ComposeBuild, "compose-build", false, "Build compose services that have a build: section before scanning")
+	scanCmd.StringVar(&config.TrivyCacheDir, "trivy-cache-dir", "", "Trivy cache directory holding its vulnerability DBs (default: trivy's own)")
+	scanCmd.BoolVar(&config.SkipDBUpdate, "skip-db-update", false, "Use the trivy DBs already in the cache instead of downloading them")
+	scanCmd.BoolVar(&config.OfflineScan, "offline-scan", false, "Run trivy without network access (implies --skip-db-update)")
+	scanCmd.IntVar(&config.Parallel, "parallel", 1, "Number of targets scanned concurrently")
+	scanCmd.BoolVar(&config.NugetHealth, "nuget-health", false, "Also report deprecated and outdated NuGet packages")
+	scanCmd.BoolVar(&config.FailOnHealth, "fail-on-health", false, "Apply --fail-on to package health findings (deprecated, outdated, abandoned, yanked) too")
 
 	// Custom argument parsing to allow flags after positional arguments
 	// The standard flag package stops parsing at the first non-flag argument.
@@ -71,7 +131,25 @@ func main() {
 		"-out": true, "--out": true,
 		"-fail-on": true, "--fail-on": true,
 		"-timeout": true, "--timeout": true,
+		"-timeout-restore": true, "--timeout-restore": true,
+		"-timeout-audit": true, "--timeout-audit": true,
+		"-timeout-build": true, "--timeout-build": true,
+		"-timeout-scanner": true, "--timeout-scanner": true,
 		"-image": true, "--image": true,
+		"-images-file": true, "--images-file": true,
+		"-dockerfile": true, "--dockerfile": true,
+		"-build-context": true, "--build-context": true,
+		"-build-target": true, "--build-target": true,
+		"-platform": true, "--platform": true,
+		"-build-arg": true, "--build-arg": true,
+		"-image-archive": true, "--image-archive": true,
+		"-oci-layout": true, "--oci-layout": true,
+		"-advisory-db": true, "--advisory-db": true,
+		"-rustsec-db": true, "--rustsec-db": true,
+		"-ruby-advisory-db": true, "--ruby-advisory-db": true,
+		"-compose-profile": true, "--compose-profile": true,
+		"-trivy-cache-dir": true, "--trivy-cache-dir": true,
+		"-parallel": true, "--parallel": true,
 	}
 
 	for i := 0; i < len(rawArgs); i++ {
@@ -121,9 +199,89 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Validate Parallel
+	if config.Parallel < 1 {
+		fmt.Fprintf(os.Stderr, "Invalid parallel value: %d (must be >= 1)\n", config.Parallel)
+		os.Exit(1)
+	}
+
+	// Validate timeouts
+	if config.TimeoutRestore < 0 || config.TimeoutAudit < 0 || config.TimeoutBuild < 0 {
+		fmt.Fprintf(os.Stderr, "Invalid phase timeout: values must be >= 0\n")
+		os.Exit(1)
+	}
+	scannerTimeouts, err := scanners.ParseScannerTimeouts(config.TimeoutScanner)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid timeout-scanner value: %v\n", err)
+		os.Exit(1)
+	}
+	for name := range scannerTimeouts {
+		if !slices.Contains(scanners.Names(), name) {
+			fmt.Fprintf(os.Stderr, "Invalid timeout-scanner value: unknown scanner %q (known: %s)\n", name, strings.Join(scanners.Names(), ", "))
+			os.Exit(1)
+		}
+	}
+	timeouts := scanners.Timeouts{
+		Restore: time.Duration(config.TimeoutRestore) * time.Second,
+		Audit:   time.Duration(config.TimeoutAudit) * time.Second,
+		Build:   time.Duration(config.TimeoutBuild) * time.Second,
+		Scanner: scannerTimeouts,
+	}
+
+	// Collect images from --image and --images-file
+	images := slices.Clone(config.Images)
+	if config.ImagesFile != "" {
+		fromFile, err := trivy.ReadImagesFile(config.ImagesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid images-file: %v\n", err)
+			os.Exit(1)
+		}
+		for _, image := range fromFile {
+			if !slices.Contains(images, image) {
+				images = append(images, image)
+			}
+		}
+	}
+
+	// External tools run in the directory of each target, so local paths are resolved here
+	for _, p := range []*string{&config.RustsecDB, &config.RubyDB, &config.TrivyCacheDir} {
+		if *p == "" {
+			continue
+		}
+		abs, err := filepath.Abs(*p)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid path %q: %v\n", *p, err)
+			os.Exit(1)
+		}
+		*p = abs
+	}
+
+	// Validate build options
+	buildArgs := make(map[string]string)
+	for _, arg := range config.BuildArgs {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			fmt.Fprintf(os.Stderr, "Invalid build-arg value: %q (expected KEY=VALUE)\n", arg)
+			os.Exit(1)
+		}
+		buildArgs[key] = value
+	}
+	dockerfile, buildContext := config.Dockerfile, config.BuildContext
+	for _, p := range []*string{&dockerfile, &buildContext} {
+		if *p == "" {
+			continue
+		}
+		abs, err := filepath.Abs(*p)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid build path: %v\n", err)
+			os.Exit(1)
+		}
+		*p = abs
+	}
+
 	// Run Detection
 	fmt.Printf("Analyzing %s ...\n", absPath)
-	detRes, err := detect.DetectStacks(absPath)
+	detRes, err := detect.DetectStacks(absPath, config.OutDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Detection failed: %v\n", err)
 		os.Exit(1)
@@ -134,13 +292,33 @@ func main() {
 	fmt.Printf("Target:     %s\n", absPath)
 	fmt.Printf("Output:     %s\n", config.OutDir)
 	fmt.Printf("Timeout:    %ds\n", config.TimeoutSec)
+	if config.TimeoutRestore > 0 || config.TimeoutAudit > 0 || config.TimeoutBuild > 0 {
+		fmt.Printf("Phases:     restore=%ds audit=%ds build=%ds (0 = global only)\n", config.TimeoutRestore, config.TimeoutAudit, config.TimeoutBuild)
+	}
+	if config.TimeoutScanner != "" {
+		fmt.Printf("Scanners:   %s\n", config.TimeoutScanner)
+	}
 	fmt.Printf("Fail On:    %s\n", config.FailOn)
+	fmt.Printf("Parallel:   %d\n", config.Parallel)
+	if config.SkipDBUpdate || config.OfflineScan {
+		fmt.Printf("Trivy DB:   %s (no update, offline=%v)\n", trivy.CacheDir(config.TrivyCacheDir), config.OfflineScan)
+	}
 
 	fmt.Println("\n[Detected Stacks]")
 	printStack("Dotnet", detRes.Dotnet)
+	printStack("Dotnet CPM", detRes.DotnetCPM)
 	printStack("NPM", detRes.Npm)
 	printStack("Bun", detRes.Bun)
+	printStack("Yarn", detRes.Yarn)
+	printStack("Pnpm", detRes.Pnpm)
+	printStack("Go", detRes.Go)
+	printStack("Python", detRes.Python)
+	printStack("JVM", detRes.Jvm)
+	printStack("Rust", detRes.Rust)
+	printStack("PHP", detRes.Php)
+	printStack("Ruby", detRes.Ruby)
 	printStack("Docker", detRes.Docker)
+	printStack("Image Archives", detRes.ImageArchives)
 
 	fmt.Println("\n[Execution]")
 
@@ -150,216 +328,49 @@ func main() {
 
 	var allFindings []model.Finding
 	var scannerErrors []report.ScannerError
-	toolsRun := make(map[string]bool)
-
-	// NPM Scanning
-	if len(detRes.Npm) > 0 {
-		fmt.Printf("Scanning %d NPM lockfiles...\n", len(detRes.Npm))
-		toolsRun["npm"] = true
-		limit := 10
-		if len(detRes.Npm) < limit {
-			limit = len(detRes.Npm)
-		}
-		for i, lockFile := range detRes.Npm[:limit] {
-			fmt.Printf("  [%d/%d] Scanning %s ... ", i+1, limit, lockFile)
-			findings, err := npm.ScanNpm(ctx, lockFile, config.TimeoutSec, config.OutDir)
-			if err != nil {
-				fmt.Printf("Failed: %v\n", err)
-				scannerErrors = append(scannerErrors, report.ScannerError{
-					Source:   "npm",
-					Location: lockFile,
-					Message:  err.Error(),
-				})
-			} else {
-				fmt.Printf("OK (%d findings)\n", len(findings))
-				allFindings = append(allFindings, findings...)
-			}
-		}
-		if len(detRes.Npm) > limit {
-			fmt.Printf("  ... skipped %d remaining lockfiles (limit 10)\n", len(detRes.Npm)-limit)
-		}
-	} else {
-		toolsRun["npm"] = false
-	}
 
-	// Bun Scanning (New)
-	if len(detRes.Bun) > 0 {
-		fmt.Printf("Scanning %d Bun lockfiles...\n", len(detRes.Bun))
-		toolsRun["bun"] = true
-		limit := 10
-		if len(detRes.Bun) < limit {
-			limit = len(detRes.Bun)
-		}
-		for i, lockFile := range detRes.Bun[:limit] {
-			fmt.Printf("  [%d/%d] Scanning %s ... ", i+1, limit, lockFile)
-			findings, err := bun.ScanBun(ctx, lockFile, config.TimeoutSec, config.OutDir)
-			if err != nil {
-				fmt.Printf("Failed: %v\n", err)
-				scannerErrors = append(scannerErrors, report.ScannerError{
-					Source:   "bun",
-					Location: lockFile,
-					Message:  err.Error(),
-				})
-			} else {
-				fmt.Printf("OK (%d findings)\n", len(findings))
-				allFindings = append(allFindings, findings...)
-			}
-		}
-		if len(detRes.Bun) > limit {
-			fmt.Printf("  ... skipped %d remaining lockfiles (limit 10)\n", len(detRes.Bun)-limit)
-		}
-	} else {
-		toolsRun["bun"] = false
+	registered := scanners.New(scanners.Options{
+		Root:        absPath,
+		OutDir:      config.OutDir,
+		Timeouts:    timeouts,
+		NoOSV:       config.NoOSV,
+		AdvisoryDB:  config.AdvisoryDB,
+		RustsecDB:   config.RustsecDB,
+		RubyDB:      config.RubyDB,
+		NugetHealth: config.NugetHealth,
+		NoContainer: config.NoContainer,
+		Images:      images,
+		DockerBuild: config.DockerBuild,
+		BaseImages:  config.BaseImages,
+
+		Dockerfile:   dockerfile,
+		BuildContext: buildContext,
+		BuildTarget:  config.BuildTarget,
+		Platform:     config.Platform,
+		BuildArgs:    buildArgs,
+
+		ImageArchive: config.ImageArchive,
+		OCILayout:    config.OCILayout,
+		TrivyFS:      config.TrivyFS,
+		TrivyConfig:  config.TrivyConfig,
+
+		ComposeProfiles: splitList(config.ComposeProfiles),
+		ComposeBuild:    config.ComposeBuild,
+
+		TrivyCacheDir:     config.TrivyCacheDir,
+		TrivySkipDBUpdate: config.SkipDBUpdate,
+		TrivyOffline:      config.OfflineScan,
+	})
+
+	jobs, toolsRun := scanners.Plan(registered, detRes, os.Stdout)
+	if config.Parallel > 1 {
+		fmt.Printf("Running %d targets with %d workers...\n", len(jobs), config.Parallel)
 	}
-
-	// Dotnet Scanning
-	shouldScanDotnet := len(detRes.Dotnet) > 0
-	if !shouldScanDotnet {
-		// Heuristic: check if obj folder exists (implies dotnet project might exist without recognized project files?)
-		// Used to be: } else if _, err := os.Stat(filepath.Join(absPath, "obj")); err == nil {
-		if _, err := os.Stat(filepath.Join(absPath, "obj")); err == nil {
-			shouldScanDotnet = true
-		}
-	}
-
-	if shouldScanDotnet {
-		toolsRun["dotnet"] = true
-		fmt.Printf("Scanning Dotnet (found %d files)...\n", len(detRes.Dotnet))
-
-		// Filter targets
-		var finalTargets []string
-		var slns []string
-		for _, f := range detRes.Dotnet {
-			if strings.HasSuffix(f, ".sln") {
-				slns = append(slns, f)
-			}
-		}
-		// Fallback: If no SLNs, use project files (csproj) if available
-		if len(slns) > 0 {
-			// Scan solutions
-			finalTargets = append(finalTargets, slns...)
-
-			// Detect orphans
-			includedProjects, err := getProjectsInSolutions(slns)
-			if err != nil {
-				// Warn but proceed with just solutions?
-				fmt.Printf("Warning: Failed to parse solutions for orphan detection: %v\n", err)
-			} else {
-				// Find orphans
-				orphanCount := 0
-				for _, projPath := range detRes.Dotnet {
-					// Skip if it's a solution
-					if strings.HasSuffix(projPath, ".sln") {
-						continue
-					}
-					// Check if included
-					if !includedProjects[projPath] {
-						finalTargets = append(finalTargets, projPath)
-						orphanCount++
-					}
-				}
-				if orphanCount > 0 {
-					fmt.Printf("  Found %d orphan projects (not in solution).\n", orphanCount)
-				}
-			}
-
-			fmt.Printf("  Targeting %d items (%d solutions + orphans).\n", len(finalTargets), len(slns))
-		} else if len(detRes.Dotnet) > 0 {
-			// If no SLNs but we have other dotnet files (which are csproj per detect logic)
-			// we target them directly.
-			finalTargets = detRes.Dotnet
-			fmt.Printf("  Targeting %d projects (no solution found).\n", len(finalTargets))
-		} else {
-			// Fallback to root scan (Scanner handles empty targets list by scanning root)
-			// This path is technically redundant if detRes.Dotnet was empty (shouldScanDotnet logic handles it),
-			// but kep for safety.
-			finalTargets = []string{}
-			fmt.Printf("  No solutions or projects found, scanning repository root.\n")
-		}
-
-		detOverride := detRes
-		detOverride.Dotnet = finalTargets
-
-		findings, errs := dotnet.ScanDotnet(ctx, absPath, detOverride, config.TimeoutSec, config.OutDir)
-
-		if len(errs) > 0 {
-			for _, e := range errs {
-				fmt.Printf("  Dotnet error: %s: %s\n", e.Location, e.Message)
-			}
-			scannerErrors = append(scannerErrors, errs...)
-		}
-		fmt.Printf("  Dotnet OK (%d findings)\n", len(findings))
-		allFindings = append(allFindings, findings...)
-	} else {
-		toolsRun["dotnet"] = false
-	}
-	// Container / Trivy Scanning
-	// Should run if: !NoContainer AND (Image provided OR (DockerBuild AND Dockerfile detected))
-	hasDockerfile := len(detRes.Docker) > 0 // Simplification: assume if "Docker" stack detected, it implies Dockerfile or similar.
-	// Actually detRes.Docker contains files. Check if any "Dockerfile" is in the list or we just trust the stack detection presence.
-	// Stack detection detects Dockerfile, docker-compose.yml etc.
-	// The requirement for "DockerBuild" implies a Dockerfile at root usually, or we build the root context.
-
-	shouldScanContainer := !config.NoContainer
-	if shouldScanContainer {
-		targetImage := config.Image
-
-		// Logic: if no image provided, but DockerBuild is requested and we detected Docker stack (or just trust user wants to build context?),
-		// we verify if we can build.
-		// Requirement: "a) --image <ref> is provided b) --docker-build is set AND a Dockerfile is detected"
-
-		if targetImage == "" && config.DockerBuild && hasDockerfile {
-			fmt.Println("Building local Docker image (depscanity:local)...")
-			// Build
-			// docker build -t depscanity:local <absPath>
-			// We need a timeout for build too? Reuse config.TimeoutSec? Or larger?
-			// Use context.
-
-			// Check docker existence
-			buildArgs := []string{"build", "-t", "depscanity:local", absPath}
-			buildRes, err := depExec.Run(ctx, "docker", buildArgs, absPath)
-
-			// Save raw build logs? "out/raw/docker-build.txt"
-			rawOutDir := filepath.Join(config.OutDir, "raw")
-			_ = os.MkdirAll(rawOutDir, 0755)
-			_ = os.WriteFile(filepath.Join(rawOutDir, "docker-build.txt"), []byte(fmt.Sprintf("STDOUT:\n%s\nSTDERR:\n%s\nEXIT: %d\nERROR: %v", buildRes.Stdout, buildRes.Stderr, buildRes.ExitCode, err)), 0644)
-
-			if err != nil || buildRes.ExitCode != 0 {
-				fmt.Printf("Docker build failed. code=%d err=%v. See report for details.\n", buildRes.ExitCode, err)
-				scannerErrors = append(scannerErrors, report.ScannerError{
-					Source:   "trivy-build",
-					Location: absPath,
-					Message:  fmt.Sprintf("Docker build failed: %v\nStderr: %s", err, buildRes.Stderr),
-				})
-				// If build failed, can't scan
-				shouldScanContainer = false
-			} else {
-				fmt.Println("Docker build successful.")
-				targetImage = "depscanity:local"
-			}
-		} else if targetImage == "" {
-			// No image provided, and conditions for auto-build not met
-			shouldScanContainer = false
-		}
-
-		if shouldScanContainer {
-			fmt.Printf("Scanning container image: %s ...\n", targetImage)
-			toolsRun["trivy"] = true
-
-			findings, errs := trivy.ScanTrivy(ctx, targetImage, config.TimeoutSec, config.OutDir)
-			if len(errs) > 0 {
-				for _, e := range errs {
-					fmt.Printf("  Trivy error: %s\n", e.Message)
-				}
-				scannerErrors = append(scannerErrors, errs...)
-			}
-			fmt.Printf("  Trivy OK (%d findings)\n", len(findings))
-			allFindings = append(allFindings, findings...)
-		} else {
-			toolsRun["trivy"] = false
-		}
-	} else {
-		toolsRun["trivy"] = false
+	var timings []report.PhaseTiming
+	for _, r := range scanners.Run(ctx, jobs, config.Parallel, timeouts, os.Stdout) {
+		allFindings = append(allFindings, r.Findings...)
+		scannerErrors = append(scannerErrors, r.Errors...)
+		timings = append(timings, r.Timings...)
 	}
 
 	// Aggregation
@@ -374,6 +385,14 @@ func main() {
 		Detected:      detRes,
 		Tools:         toolsRun,
 		ScannerErrors: scannerErrors,
+		Timings:       timings,
+	}
+	// Record the trivy databases the scan used
+	for _, tool := range []string{"trivy", "trivy-fs", "jvm", "base-image", "compose"} {
+		if toolsRun[tool] {
+			meta.Databases = trivy.Databases(config.TrivyCacheDir)
+			break
+		}
 	}
 
 	if err := report.Generate(config.OutDir, meta, uniqueFindings); err != nil {
@@ -386,6 +405,10 @@ func main() {
 	failSev, _ := model.ParseSeverity(config.FailOn)
 	maxSevRank := 0
 	for _, f := range uniqueFindings {
+		// Package health findings are informational unless the policy opts in
+		if f.IsPackageHealth() && !config.FailOnHealth {
+			continue
+		}
 		r := f.Severity.Rank()
 		if r > maxSevRank {
 			maxSevRank = r
@@ -413,8 +436,8 @@ func main() {
 	fmt.Println("\n[Options]")
 	fmt.Printf("OSV Scanner:       %v\n", !config.NoOSV)
 	fmt.Printf("Container Scan:    %v\n", !config.NoContainer)
-	if config.Image != "" {
-		fmt.Printf("Target Image:      %s\n", config.Image)
+	for _, image := range images {
+		fmt.Printf("Target Image:      %s\n", image)
 	}
 	if config.DockerBuild {
 		fmt.Printf("Docker Build:      Enabled\n")
@@ -433,59 +456,68 @@ func printStack(name string, files []string) {
 	}
 }
 
+// stringList is a flag that can be given several times.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func printUsage() {
 	fmt.Println("Usage: depscanity scan <path> [flags]")
+	fmt.Println("       depscanity db import [--cache-dir <dir>] [--java-db <javadb.tar.gz>] [<db.tar.gz>]")
 	fmt.Println("Flags:")
 	fmt.Println("  --out          Output directory (default: depscanity_out)")
 	fmt.Println("  --fail-on      Fail severity threshold (default: high)")
 	fmt.Println("  --timeout      Timeout in seconds (default: 600)")
+	fmt.Println("  --timeout-restore, --timeout-audit, --timeout-build")
+	fmt.Println("                 Per-phase timeouts in seconds (default: 0 = global only)")
+	fmt.Println("  --timeout-scanner")
+	fmt.Println("                 Per-target timeouts by scanner, e.g. npm=120,dotnet=600")
 	fmt.Println("  --no-osv       Disable OSV scanner")
+	fmt.Println("  --advisory-db  Local OSV database dump for offline matching")
+	fmt.Println("  --rustsec-db   Local RustSec advisory-db for cargo audit (offline)")
+	fmt.Println("  --ruby-advisory-db")
+	fmt.Println("                 Local ruby-advisory-db for bundle-audit")
 	fmt.Println("  --no-container Disable container scanning")
-	fmt.Println("  --image        Scan specific docker image")
+	fmt.Println("  --image        Scan specific docker image (repeatable)")
+	fmt.Println("  --images-file  File with image references to scan, one per line")
 	fmt.Println("  --docker-build Build docker image before scanning")
-}
-
-// getProjectsInSolutions parses .sln files to find included projects.
-// Returns a map of absolute paths to projects that are PART of a solution.
-func getProjectsInSolutions(slnPaths []string) (map[string]bool, error) {
-	included := make(map[string]bool)
-
-	for _, slnPath := range slnPaths {
-		file, err := os.Open(slnPath)
-		if err != nil {
-			return nil, err
-		}
-		defer file.Close()
-
-		baseDir := filepath.Dir(slnPath)
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			line := strings.TrimSpace(scanner.Text())
-			// Format: Project("{GUID}") = "Name", "Path\To\Project.csproj", "{GUID}"
-			if strings.HasPrefix(line, "Project(") {
-				parts := strings.Split(line, "=")
-				if len(parts) >= 2 {
-					// valid project line
-					// split by comma to get the path (2nd quoted string)
-					segments := strings.Split(parts[1], ",")
-					if len(segments) >= 3 {
-						// The path is in the second segment, quoted.
-						rawPath := strings.TrimSpace(segments[1])
-						rawPath = strings.Trim(rawPath, "\"")
-
-						// Convert Windows path separators to OS specific if needed, but standard library handles / usually
-						// However SLN uses backslashes
-						cleanPath := strings.ReplaceAll(rawPath, "\\", string(os.PathSeparator))
-
-						absPath := filepath.Join(baseDir, cleanPath)
-
-						// Normalize path
-						absPath, _ = filepath.Abs(absPath)
-						included[absPath] = true
-					}
-				}
-			}
-		}
-	}
-	return included, nil
+	fmt.Println("  --dockerfile   Dockerfile to build (default: every detected Dockerfile)")
+	fmt.Println("  --build-context, --build-target, --platform")
+	fmt.Println("                 Build context directory, target stage and platform")
+	fmt.Println("  --build-arg    Build argument KEY=VALUE (repeatable)")
+	fmt.Println("  --image-archive")
+	fmt.Println("                 Scan an image tarball (docker save / OCI) without Docker")
+	fmt.Println("  --oci-layout   Scan an OCI image layout directory without Docker")
+	fmt.Println("  --base-images  Scan Dockerfile base images (FROM) without building")
+	fmt.Println("  --trivy-fs     Scan lockfiles without a native scanner with trivy fs")
+	fmt.Println("  --trivy-config Report IaC misconfigurations found by trivy config")
+	fmt.Println("  --compose-profile")
+	fmt.Println("                 Compose profiles to activate, comma-separated")
+	fmt.Println("  --compose-build")
+	fmt.Println("                 Build compose services with a build: section")
+	fmt.Println("  --trivy-cache-dir")
+	fmt.Println("                 Trivy cache directory holding its vulnerability DBs")
+	fmt.Println("  --skip-db-update")
+	fmt.Println("                 Use the trivy DBs already in the cache")
+	fmt.Println("  --offline-scan Run trivy without network access (implies --skip-db-update)")
+	fmt.Println("  --parallel     Number of targets scanned concurrently (default: 1)")
+	fmt.Println("  --nuget-health Also report deprecated and outdated NuGet packages")
+	fmt.Println("  --fail-on-health")
+	fmt.Println("                 Let deprecated/outdated findings trip --fail-on")
 }
@@ -52,13 +52,42 @@ func AggregateFindings(findings []model.Finding) []model.Finding {
 		}
 
 		// VulnerabilityID ASC
-		return fi.VulnerabilityID < fj.VulnerabilityID
+		if fi.VulnerabilityID != fj.VulnerabilityID {
+			return fi.VulnerabilityID < fj.VulnerabilityID
+		}
+
+		// Tiebreaks over the rest of the dedupe key, so map iteration never decides the order
+		if fi.InstalledVersion != fj.InstalledVersion {
+			return fi.InstalledVersion < fj.InstalledVersion
+		}
+		if fi.Location != fj.Location {
+			return fi.Location < fj.Location
+		}
+		if ii, ij := fmt.Sprint(fi.Metadata["image"]), fmt.Sprint(fj.Metadata["image"]); ii != ij {
+			return ii < ij
+		}
+		return startLine(fi) < startLine(fj)
 	})
 
 	return result
 }
 
+// startLine returns the start_line metadata of misconfiguration findings (0 when absent).
+func startLine(f model.Finding) int {
+	line, _ := f.Metadata["start_line"].(int)
+	return line
+}
+
 func dedupeKey(f model.Finding) string {
+	// The same check fails independently in every file and line
+	if f.Class == model.ClassMisconfiguration {
+		return fmt.Sprintf("%s|%s|%s|%s|%v", f.Source, f.VulnerabilityID, f.Location, f.Package, f.Metadata["start_line"])
+	}
+	// Container findings are kept per image so every image section stays complete
+	if image, ok := f.Metadata["image"]; ok {
+		return fmt.Sprintf("%s|%s|%s|%s|%s|%s|%v",
+			f.Source, f.Ecosystem, f.Package, f.InstalledVersion, f.VulnerabilityID, f.Severity, image)
+	}
 	// source|ecosystem|package|version|vulnID|severity
 	return fmt.Sprintf("%s|%s|%s|%s|%s|%s",
 		f.Source, f.Ecosystem, f.Package, f.InstalledVersion, f.VulnerabilityID, f.Severity)
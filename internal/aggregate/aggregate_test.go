@@ -1,6 +1,8 @@
 package aggregate
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 
 	"depscanity/internal/model"
@@ -53,3 +55,97 @@ func TestAggregateFindings(t *testing.T) {
 		t.Errorf("expected third finding to be Low, got %s", result[2].Severity)
 	}
 }
+
+func TestAggregateFindings_Misconfigurations(t *testing.T) {
+	check := model.Finding{
+		Source:          "trivy-config",
+		Ecosystem:       "dockerfile",
+		VulnerabilityID: "DS002",
+		Severity:        model.SeverityHigh,
+		Location:        "api/Dockerfile",
+		Class:           model.ClassMisconfiguration,
+	}
+	other := check
+	other.Location = "worker/Dockerfile"
+
+	result := AggregateFindings([]model.Finding{check, other, check})
+	if len(result) != 2 {
+		t.Errorf("expected the same check in two files to be kept apart, got %d findings", len(result))
+	}
+}
+
+func TestAggregateFindings_PerImage(t *testing.T) {
+	api := model.Finding{
+		Source:           "trivy",
+		Ecosystem:        "debian",
+		Package:          "libc6",
+		InstalledVersion: "2.36-9",
+		VulnerabilityID:  "CVE-2024-2961",
+		Severity:         model.SeverityHigh,
+		Metadata:         map[string]any{"image": "acme/api:1.0"},
+	}
+	worker := api
+	worker.Metadata = map[string]any{"image": "acme/worker:1.0"}
+
+	result := AggregateFindings([]model.Finding{api, worker, api})
+	if len(result) != 2 {
+		t.Errorf("expected one finding per image, got %d", len(result))
+	}
+}
+
+func TestAggregateFindings_Deterministic(t *testing.T) {
+	base := model.Finding{
+		Source:           "trivy",
+		Ecosystem:        "debian",
+		Package:          "libc6",
+		InstalledVersion: "2.36-9",
+		VulnerabilityID:  "CVE-2024-2961",
+		Severity:         model.SeverityHigh,
+	}
+	var input []model.Finding
+	for _, image := range []string{"acme/worker:1.0", "acme/api:1.0", "acme/web:1.0"} {
+		f := base
+		f.Metadata = map[string]any{"image": image}
+		input = append(input, f)
+	}
+	for _, version := range []string{"2.36-9+deb12u4", "2.36-9+deb12u1"} {
+		f := base
+		f.Source = "osv"
+		f.InstalledVersion = version
+		input = append(input, f)
+	}
+	for _, line := range []int{12, 3} {
+		input = append(input, model.Finding{
+			Source:          "trivy-config",
+			VulnerabilityID: "DS002",
+			Severity:        model.SeverityHigh,
+			Location:        "/repo/Dockerfile",
+			Class:           model.ClassMisconfiguration,
+			Metadata:        map[string]any{"start_line": line},
+		})
+	}
+
+	first := AggregateFindings(input)
+	for run := 0; run < 20; run++ {
+		if got := AggregateFindings(input); !reflect.DeepEqual(got, first) {
+			t.Fatalf("run %d produced a different order", run)
+		}
+	}
+
+	var order []string
+	for _, f := range first {
+		order = append(order, fmt.Sprintf("%s %s %v %v", f.Source, f.InstalledVersion, f.Metadata["image"], f.Metadata["start_line"]))
+	}
+	want := []string{
+		"osv 2.36-9+deb12u1 <nil> <nil>",
+		"osv 2.36-9+deb12u4 <nil> <nil>",
+		"trivy 2.36-9 acme/api:1.0 <nil>",
+		"trivy 2.36-9 acme/web:1.0 <nil>",
+		"trivy 2.36-9 acme/worker:1.0 <nil>",
+		"trivy-config  <nil> 3",
+		"trivy-config  <nil> 12",
+	}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("unexpected order:\n%v\nwant\n%v", order, want)
+	}
+}
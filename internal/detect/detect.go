@@ -1,6 +1,8 @@
 package detect
 
 import (
+	"archive/tar"
+	"bufio"
 	"os"
 	"path/filepath"
 	"sort"
@@ -11,7 +13,20 @@ type DetectionResult struct {
 	Dotnet []string
 	Npm    []string
 	Bun    []string
+	Yarn   []string
+	Pnpm   []string
+	Go     []string
+	Python []string
+	Jvm    []string
+	Rust   []string
+	Php    []string
+	Ruby   []string
 	Docker []string
+
+	// DotnetCPM holds Directory.Packages.props files (NuGet Central Package Management).
+	DotnetCPM []string
+	// ImageArchives holds container image tarballs (docker save or OCI) found in the tree, e.g. build output.
+	ImageArchives []string
 }
 
 // Ignored directories (exact match on folder name)
@@ -22,16 +37,25 @@ var ignoredDirs = map[string]struct{}{
 	"obj":          {},
 	".venv":        {},
 	"venv":         {},
+	"vendor":       {},
+	".gradle":      {},
 }
 
 // DetectStacks scans the root directory for relevant files.
-// It skips ignored directories and returns sorted absolute paths.
-func DetectStacks(root string) (DetectionResult, error) {
+// It skips ignored directories and the output directory outDir (whose exported images
+// would otherwise be rescanned) and returns sorted absolute paths.
+func DetectStacks(root, outDir string) (DetectionResult, error) {
 	var res DetectionResult
 	absRoot, err := filepath.Abs(root)
 	if err != nil {
 		return res, err
 	}
+	absOut := ""
+	if outDir != "" {
+		if absOut, err = filepath.Abs(outDir); err != nil {
+			return res, err
+		}
+	}
 
 	err = filepath.Walk(absRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -43,15 +67,22 @@ func DetectStacks(root string) (DetectionResult, error) {
 			if _, ok := ignoredDirs[info.Name()]; ok {
 				return filepath.SkipDir
 			}
+			if path == absOut && path != absRoot {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
 		// File detection
 		filename := strings.ToLower(info.Name())
-		// Dotnet: *.sln, *.csproj
-		if strings.HasSuffix(filename, ".sln") || strings.HasSuffix(filename, ".csproj") {
+		// Dotnet: *.sln, *.slnx, *.csproj, *.fsproj, *.vbproj
+		if IsDotnetSolution(filename) || strings.HasSuffix(filename, ".csproj") ||
+			strings.HasSuffix(filename, ".fsproj") || strings.HasSuffix(filename, ".vbproj") {
 			res.Dotnet = append(res.Dotnet, path)
 		}
+		if filename == "directory.packages.props" {
+			res.DotnetCPM = append(res.DotnetCPM, path)
+		}
 
 		// Npm: package-lock.json (exact match, though we use lower for case-insensitive check)
 		if filename == "package-lock.json" {
@@ -63,13 +94,60 @@ func DetectStacks(root string) (DetectionResult, error) {
 			res.Bun = append(res.Bun, path)
 		}
 
-		// Docker: Dockerfile, docker-compose.yml|yaml, compose.yml|yaml
-		if filename == "dockerfile" ||
+		// Yarn: yarn.lock (classic v1 or Berry, see IsYarnBerry)
+		if filename == "yarn.lock" {
+			res.Yarn = append(res.Yarn, path)
+		}
+
+		// Pnpm: pnpm-lock.yaml
+		if filename == "pnpm-lock.yaml" {
+			res.Pnpm = append(res.Pnpm, path)
+		}
+
+		// Go: go.mod (one per module root)
+		if filename == "go.mod" {
+			res.Go = append(res.Go, path)
+		}
+
+		// Python: requirements*.txt, poetry.lock, Pipfile.lock, uv.lock
+		if (strings.HasPrefix(filename, "requirements") && strings.HasSuffix(filename, ".txt")) ||
+			filename == "poetry.lock" || filename == "pipfile.lock" || filename == "uv.lock" {
+			res.Python = append(res.Python, path)
+		}
+
+		// JVM: pom.xml, build.gradle(.kts), gradle.lockfile
+		if filename == "pom.xml" || filename == "build.gradle" || filename == "build.gradle.kts" ||
+			filename == "gradle.lockfile" {
+			res.Jvm = append(res.Jvm, path)
+		}
+
+		// Rust: Cargo.lock (one per workspace root)
+		if filename == "cargo.lock" {
+			res.Rust = append(res.Rust, path)
+		}
+
+		// PHP: composer.lock
+		if filename == "composer.lock" {
+			res.Php = append(res.Php, path)
+		}
+
+		// Ruby: Gemfile.lock
+		if filename == "gemfile.lock" {
+			res.Ruby = append(res.Ruby, path)
+		}
+
+		// Docker: Dockerfile(.<x>), <x>.Dockerfile, docker-compose.yml|yaml, compose.yml|yaml
+		if IsDockerfile(filename) ||
 			filename == "docker-compose.yml" || filename == "docker-compose.yaml" ||
 			filename == "compose.yml" || filename == "compose.yaml" {
 			res.Docker = append(res.Docker, path)
 		}
 
+		// Image archives: *.tar written by docker save, Kaniko, Buildah, ...
+		if strings.HasSuffix(filename, ".tar") && IsImageArchive(path) {
+			res.ImageArchives = append(res.ImageArchives, path)
+		}
+
 		return nil
 	})
 
@@ -81,7 +159,80 @@ func DetectStacks(root string) (DetectionResult, error) {
 	sort.Strings(res.Dotnet)
 	sort.Strings(res.Npm)
 	sort.Strings(res.Bun)
+	sort.Strings(res.Yarn)
+	sort.Strings(res.Pnpm)
+	sort.Strings(res.Go)
+	sort.Strings(res.Python)
+	sort.Strings(res.Jvm)
+	sort.Strings(res.Rust)
+	sort.Strings(res.Php)
+	sort.Strings(res.Ruby)
+	sort.Strings(res.DotnetCPM)
 	sort.Strings(res.Docker)
+	sort.Strings(res.ImageArchives)
 
 	return res, nil
 }
+
+// IsDotnetSolution reports whether path is a classic (.sln) or XML (.slnx) solution file.
+func IsDotnetSolution(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasSuffix(lower, ".sln") || strings.HasSuffix(lower, ".slnx")
+}
+
+// IsYarnBerry reports whether a yarn.lock was written by Yarn 2+ (Berry).
+// Berry lockfiles are YAML with a top-level __metadata entry; classic v1 lockfiles are not.
+func IsYarnBerry(lockPath string) (bool, error) {
+	file, err := os.Open(lockPath)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "__metadata:") {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// IsDockerfile reports whether path names a Dockerfile (Dockerfile, Dockerfile.<x> or <x>.Dockerfile).
+// Per-Dockerfile ignore files (Dockerfile.dockerignore) are not Dockerfiles.
+func IsDockerfile(path string) bool {
+	base := strings.ToLower(filepath.Base(path))
+	if strings.HasSuffix(base, ".dockerignore") {
+		return false
+	}
+	return base == "dockerfile" || strings.HasPrefix(base, "dockerfile.") || strings.HasSuffix(base, ".dockerfile")
+}
+
+// IsImageArchive reports whether path is a tarball of a container image: a docker save
+// archive (manifest.json) or an OCI image layout (oci-layout, index.json).
+func IsImageArchive(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	// The reader seeks over layer contents, so only the headers are read
+	tr := tar.NewReader(file)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			return false
+		}
+		switch strings.TrimPrefix(hdr.Name, "./") {
+		case "manifest.json", "oci-layout", "index.json":
+			return true
+		}
+	}
+}
+
+// IsOCILayout reports whether dir is an OCI image layout directory.
+func IsOCILayout(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "oci-layout"))
+	return err == nil && !info.IsDir()
+}
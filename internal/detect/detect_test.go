@@ -1,6 +1,7 @@
 package detect
 
 import (
+	"archive/tar"
 	"os"
 	"path/filepath"
 	"testing"
@@ -19,12 +20,34 @@ func TestDetectStacks(t *testing.T) {
 		"root.sln",
 		"backend/app.csproj",
 		"frontend/package-lock.json",
+		"web/yarn.lock",
+		"services/pnpm-lock.yaml",
+		"tools/go.mod",
+		"api/requirements.txt",
+		"api/requirements-dev.txt",
+		"worker/poetry.lock",
+		"legacy/Pipfile.lock",
+		"cli/uv.lock",
+		".venv/lib/requirements.txt", // Should be ignored
+		"orders/pom.xml",
+		"billing/build.gradle.kts",
+		"billing/gradle.lockfile",
+		"billing/.gradle/8.5/build.gradle", // Should be ignored
+		"cli/Cargo.lock",
+		"shop/composer.lock",
+		"blog/Gemfile.lock",
+		"fsharp/Lib.fsproj",
+		"vb/Legacy.vbproj",
+		"modern/App.slnx",
+		"Directory.Packages.props",
 		"Dockerfile",
+		"services/api/Dockerfile.prod",
 		"deploy/compose.yml",
-		"node_modules/ignored-package/package.json", // Should be ignored
-		".git/config",    // Should be ignored
-		"bin/output.dll", // Should be ignored
+		"node_modules/ignored-package/package.json",   // Should be ignored
+		".git/config",                                 // Should be ignored
+		"bin/output.dll",                              // Should be ignored
 		"nested/node_modules/stuff/package-lock.json", // Should be ignored
+		"tools/vendor/example.com/lib/go.mod",         // Should be ignored
 	}
 
 	for _, f := range files {
@@ -38,22 +61,57 @@ func TestDetectStacks(t *testing.T) {
 		}
 	}
 
-	res, err := DetectStacks(tmpDir)
+	res, err := DetectStacks(tmpDir, "")
 	if err != nil {
 		t.Fatalf("DetectStacks failed: %v", err)
 	}
 
 	// Verify Dotnet
-	if len(res.Dotnet) != 2 {
-		t.Errorf("expected 2 dotnet files, got %d", len(res.Dotnet))
+	if len(res.Dotnet) != 5 {
+		t.Errorf("expected 5 dotnet files, got %d", len(res.Dotnet))
 	}
 	// Verify Npm
 	if len(res.Npm) != 1 {
 		t.Errorf("expected 1 npm file, got %d", len(res.Npm))
 	}
+	// Verify Yarn
+	if len(res.Yarn) != 1 {
+		t.Errorf("expected 1 yarn file, got %d", len(res.Yarn))
+	}
+	// Verify Pnpm
+	if len(res.Pnpm) != 1 {
+		t.Errorf("expected 1 pnpm file, got %d", len(res.Pnpm))
+	}
+	// Verify Go
+	if len(res.Go) != 1 {
+		t.Errorf("expected 1 go.mod, got %d", len(res.Go))
+	}
+	// Verify Python
+	if len(res.Python) != 5 {
+		t.Errorf("expected 5 python files, got %d", len(res.Python))
+	}
+	// Verify JVM
+	if len(res.Jvm) != 3 {
+		t.Errorf("expected 3 jvm files, got %d", len(res.Jvm))
+	}
+	// Verify Rust
+	if len(res.Rust) != 1 {
+		t.Errorf("expected 1 Cargo.lock, got %d", len(res.Rust))
+	}
+	// Verify PHP and Ruby
+	if len(res.Php) != 1 {
+		t.Errorf("expected 1 composer.lock, got %d", len(res.Php))
+	}
+	if len(res.Ruby) != 1 {
+		t.Errorf("expected 1 Gemfile.lock, got %d", len(res.Ruby))
+	}
+	// Verify .NET CPM
+	if len(res.DotnetCPM) != 1 {
+		t.Errorf("expected 1 Directory.Packages.props, got %d", len(res.DotnetCPM))
+	}
 	// Verify Docker
-	if len(res.Docker) != 2 {
-		t.Errorf("expected 2 docker files, got %d", len(res.Docker))
+	if len(res.Docker) != 3 {
+		t.Errorf("expected 3 docker files, got %d", len(res.Docker))
 	}
 
 	// Check ignored paths specifically
@@ -63,3 +121,89 @@ func TestDetectStacks(t *testing.T) {
 		}
 	}
 }
+
+func TestIsYarnBerry(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	classic := filepath.Join(tmpDir, "classic.lock")
+	os.WriteFile(classic, []byte("# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.\n# yarn lockfile v1\n\nminimist@^1.2.0:\n  version \"1.2.5\"\n"), 0644)
+	berry := filepath.Join(tmpDir, "berry.lock")
+	os.WriteFile(berry, []byte("# This file is generated by running \"yarn install\"\n\n__metadata:\n  version: 6\n  cacheKey: 8\n"), 0644)
+
+	if isBerry, err := IsYarnBerry(classic); err != nil || isBerry {
+		t.Errorf("expected classic lockfile, got berry=%v err=%v", isBerry, err)
+	}
+	if isBerry, err := IsYarnBerry(berry); err != nil || !isBerry {
+		t.Errorf("expected berry lockfile, got berry=%v err=%v", isBerry, err)
+	}
+}
+
+func TestIsImageArchive(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTar := func(name string, entries ...string) string {
+		path := filepath.Join(tmpDir, name)
+		file, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		tw := tar.NewWriter(file)
+		for _, entry := range entries {
+			if err := tw.WriteHeader(&tar.Header{Name: entry, Mode: 0644, Size: 2}); err != nil {
+				t.Fatal(err)
+			}
+			tw.Write([]byte("{}"))
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	saved := writeTar("app.tar", "3f1a.json", "3f1a/layer.tar", "manifest.json", "repositories")
+	oci := writeTar("oci.tar", "./oci-layout", "./blobs/sha256/abcd")
+	source := writeTar("src.tar", "src/main.go", "src/go.mod")
+	notTar := filepath.Join(tmpDir, "notes.tar")
+	os.WriteFile(notTar, []byte("not a tarball"), 0644)
+
+	if !IsImageArchive(saved) || !IsImageArchive(oci) {
+		t.Error("expected docker save and OCI tarballs to be image archives")
+	}
+	if IsImageArchive(source) || IsImageArchive(notTar) {
+		t.Error("expected source tarball and garbage to be rejected")
+	}
+
+	res, err := DetectStacks(tmpDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.ImageArchives) != 2 {
+		t.Errorf("expected 2 image archives, got %v", res.ImageArchives)
+	}
+
+	// Images exported into the output directory by a previous run are not rescanned
+	outDir := filepath.Join(tmpDir, "depscanity_out")
+	os.MkdirAll(filepath.Join(outDir, "images"), 0755)
+	writeTar("depscanity_out/images/api.tar", "manifest.json")
+	res, err = DetectStacks(tmpDir, outDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.ImageArchives) != 2 {
+		t.Errorf("expected the output directory to be skipped, got %v", res.ImageArchives)
+	}
+}
+
+func TestIsDockerfile(t *testing.T) {
+	for _, path := range []string{"Dockerfile", "api/Dockerfile.prod", "worker.dockerfile"} {
+		if !IsDockerfile(path) {
+			t.Errorf("expected %s to be a Dockerfile", path)
+		}
+	}
+	for _, path := range []string{"compose.yml", "dist/app.tar", "Dockerfiles/README.md", "api/Dockerfile.dockerignore"} {
+		if IsDockerfile(path) {
+			t.Errorf("expected %s not to be a Dockerfile", path)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Package dockerfile reads the stages and instructions of Dockerfiles.
+package dockerfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Stage is a FROM instruction of a Dockerfile.
+type Stage struct {
+	Index int
+	// Name is the AS alias, empty when the stage is unnamed.
+	Name string
+	// From is the image reference with global ARG defaults substituted; it may name an earlier stage.
+	From string
+	Line int
+	// Instructions are the instructions of the stage after its FROM line.
+	Instructions []Instruction
+}
+
+// Instruction is a Dockerfile instruction, with continuation lines joined.
+type Instruction struct {
+	// Keyword is the upper-case instruction name (RUN, COPY, ...).
+	Keyword string
+	Args    string
+	Line    int
+}
+
+// CreatesLayer reports whether the instruction adds a filesystem layer to the image.
+func (i Instruction) CreatesLayer() bool {
+	switch i.Keyword {
+	case "RUN", "COPY", "ADD":
+		return true
+	}
+	return false
+}
+
+// Label identifies the stage like docker does: its alias, or its index.
+func (s Stage) Label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return strconv.Itoa(s.Index)
+}
+
+// Parse reads the stages of a Dockerfile. ARG instructions before the first FROM are the
+// only ones visible to FROM; their defaults are substituted unless buildArgs overrides them.
+func Parse(data []byte, buildArgs map[string]string) ([]Stage, error) {
+	args := make(map[string]string)
+	var stages []Stage
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	lineNum, startLine := 0, 0
+	var instruction strings.Builder
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if instruction.Len() == 0 {
+			startLine = lineNum
+		}
+
+		// Continuation lines end with a backslash
+		if strings.HasSuffix(line, "\\") {
+			instruction.WriteString(strings.TrimSuffix(line, "\\"))
+			instruction.WriteString(" ")
+			continue
+		}
+		instruction.WriteString(line)
+		text := instruction.String()
+		instruction.Reset()
+
+		keyword, rest, _ := strings.Cut(text, " ")
+		keyword = strings.ToUpper(keyword)
+		rest = strings.TrimSpace(rest)
+		switch keyword {
+		case "ARG":
+			if len(stages) > 0 {
+				continue
+			}
+			for _, decl := range strings.Fields(rest) {
+				name, value, hasDefault := strings.Cut(decl, "=")
+				if v, ok := buildArgs[name]; ok {
+					args[name] = v
+				} else if hasDefault {
+					args[name] = strings.Trim(value, `"'`)
+				}
+			}
+		case "FROM":
+			stage, err := parseFrom(rest, args)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", startLine, err)
+			}
+			stage.Index = len(stages)
+			stage.Line = startLine
+			stages = append(stages, stage)
+			continue
+		}
+		if len(stages) > 0 {
+			last := &stages[len(stages)-1]
+			last.Instructions = append(last.Instructions, Instruction{Keyword: keyword, Args: rest, Line: startLine})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+// parseFrom parses "FROM [--platform=<p>] <image> [AS <name>]".
+func parseFrom(rest string, args map[string]string) (Stage, error) {
+	var fields []string
+	for _, f := range strings.Fields(rest) {
+		if strings.HasPrefix(f, "--") {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return Stage{}, fmt.Errorf("FROM without image")
+	}
+
+	stage := Stage{From: expandArgs(fields[0], args)}
+	if len(fields) >= 3 && strings.EqualFold(fields[1], "AS") {
+		stage.Name = fields[2]
+	}
+	return stage, nil
+}
+
+// expandArgs substitutes $VAR, ${VAR}, ${VAR:-default} and ${VAR:+alternative}.
+func expandArgs(s string, args map[string]string) string {
+	return os.Expand(s, func(expr string) string {
+		if name, def, ok := strings.Cut(expr, ":-"); ok {
+			if v := args[name]; v != "" {
+				return v
+			}
+			return def
+		}
+		if name, alt, ok := strings.Cut(expr, ":+"); ok {
+			if args[name] != "" {
+				return alt
+			}
+			return ""
+		}
+		return args[expr]
+	})
+}
+
+// Chain returns the stages an image built with --target target is made of: the target stage
+// (the last stage when target is empty) preceded by the earlier stages it is built FROM.
+// It returns nil when the target stage does not exist.
+func Chain(stages []Stage, target string) []Stage {
+	index := len(stages) - 1
+	if target != "" {
+		index = -1
+		for i, stage := range stages {
+			if strings.EqualFold(stage.Name, target) || stage.Label() == target {
+				index = i
+				break
+			}
+		}
+	}
+	if index < 0 {
+		return nil
+	}
+
+	chain := []Stage{stages[index]}
+	for {
+		parent := -1
+		for i := 0; i < chain[0].Index; i++ {
+			if stages[i].Name != "" && strings.EqualFold(stages[i].Name, chain[0].From) {
+				parent = i
+			}
+		}
+		if parent < 0 {
+			return chain
+		}
+		chain = append([]Stage{stages[parent]}, chain...)
+	}
+}
@@ -0,0 +1,92 @@
+package dockerfile
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stages, err := Parse(data, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(stages) != 6 {
+		t.Fatalf("expected 6 stages, got %d", len(stages))
+	}
+
+	build := stages[0]
+	if build.Name != "build" || build.From != "golang:1.22-alpine" || build.Line != 6 {
+		t.Errorf("unexpected build stage: %+v", build)
+	}
+	if stages[2].Name != "runtime" || stages[2].From != "docker.io/library/alpine:3.19" {
+		t.Errorf("expected lowercase 'as' alias and ARG defaults, got %+v", stages[2])
+	}
+	if stages[4].Label() != "4" {
+		t.Errorf("expected unnamed stage to be labelled by index, got %s", stages[4].Label())
+	}
+
+	want := []Instruction{
+		{Keyword: "WORKDIR", Args: "/src", Line: 7},
+		{Keyword: "COPY", Args: ". .", Line: 8},
+		{Keyword: "RUN", Args: "go build  -o /out/app  ./cmd/app", Line: 9},
+	}
+	if !reflect.DeepEqual(build.Instructions, want) {
+		t.Errorf("unexpected build instructions: %+v", build.Instructions)
+	}
+	if !build.Instructions[2].CreatesLayer() || build.Instructions[0].CreatesLayer() {
+		t.Errorf("expected RUN to create a layer and WORKDIR not to")
+	}
+}
+
+func TestParse_BuildArgs(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stages, err := Parse(data, map[string]string{"GO_VERSION": "1.23", "ALPINE_TAG": "3.20"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if stages[0].From != "golang:1.23-alpine" || stages[2].From != "docker.io/library/alpine:3.20" {
+		t.Errorf("expected build args to override defaults, got %s / %s", stages[0].From, stages[2].From)
+	}
+}
+
+func TestChain(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "Dockerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stages, err := Parse(data, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	labels := func(chain []Stage) []string {
+		var out []string
+		for _, stage := range chain {
+			out = append(out, stage.Label())
+		}
+		return out
+	}
+	if got := labels(Chain(stages, "test")); !reflect.DeepEqual(got, []string{"build", "test"}) {
+		t.Errorf("expected test to be built from build, got %v", got)
+	}
+	if got := labels(Chain(stages, "runtime")); !reflect.DeepEqual(got, []string{"runtime"}) {
+		t.Errorf("expected runtime to stand alone, got %v", got)
+	}
+	if got := labels(Chain(stages, "")); !reflect.DeepEqual(got, []string{"5"}) {
+		t.Errorf("expected the last stage by default, got %v", got)
+	}
+	if Chain(stages, "missing") != nil {
+		t.Errorf("expected no chain for an unknown target")
+	}
+}
@@ -12,5 +12,35 @@ type Finding struct {
 	Title            *string        `json:"Title"`
 	URL              *string        `json:"URL"`
 	Location         string         `json:"Location"`
+	Class            FindingClass   `json:"Class,omitempty"`
 	Metadata         map[string]any `json:"Metadata"`
 }
+
+// FindingClass separates vulnerabilities from package health findings and misconfigurations.
+// Scanners leave it empty for vulnerabilities.
+type FindingClass string
+
+const (
+	ClassVulnerability    FindingClass = ""
+	ClassDeprecated       FindingClass = "deprecated"
+	ClassOutdated         FindingClass = "outdated"
+	ClassAbandoned        FindingClass = "abandoned"
+	ClassYanked           FindingClass = "yanked"
+	ClassInsecureSource   FindingClass = "insecure-source"
+	ClassMisconfiguration FindingClass = "misconfiguration"
+)
+
+// IsVulnerability reports whether the finding is a vulnerability (the default class).
+func (f Finding) IsVulnerability() bool {
+	return f.Class == ClassVulnerability
+}
+
+// IsPackageHealth reports whether the finding is a deprecated, outdated, abandoned or yanked
+// package. An insecure package source is a supply-chain risk and is not package health.
+func (f Finding) IsPackageHealth() bool {
+	switch f.Class {
+	case ClassDeprecated, ClassOutdated, ClassAbandoned, ClassYanked:
+		return true
+	}
+	return false
+}
@@ -18,6 +18,18 @@ type ReportMeta struct {
 	Detected      detect.DetectionResult `json:"detected"`
 	Tools         map[string]bool        `json:"tools"`
 	ScannerErrors []ScannerError         `json:"scanner_errors"`
+	Timings       []PhaseTiming          `json:"timings"`
+	Databases     []DBInfo               `json:"databases,omitempty"`
+}
+
+// DBInfo describes a vulnerability database used by the scan, as read from its metadata.
+type DBInfo struct {
+	Name         string `json:"name"`
+	Path         string `json:"path"`
+	Version      int    `json:"version"`
+	UpdatedAt    string `json:"updated_at"`
+	NextUpdate   string `json:"next_update,omitempty"`
+	DownloadedAt string `json:"downloaded_at,omitempty"`
 }
 
 type ScannerError struct {
@@ -26,11 +38,54 @@ type ScannerError struct {
 	Message  string `json:"message"`
 }
 
+// PhaseTiming records how long a scanner phase took for a target and whether it hit its budget.
+type PhaseTiming struct {
+	Source     string `json:"source"`
+	Location   string `json:"location"`
+	Phase      string `json:"phase"`
+	Command    string `json:"command,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	ExitCode   int    `json:"exit_code"`
+	TimedOut   bool   `json:"timed_out"`
+}
+
 type Report struct {
 	Meta     ReportMeta      `json:"meta"`
 	Findings []model.Finding `json:"findings"`
 }
 
+// sourceSection describes a per-source section of report.md.
+// When metaKey is set, the section gets an extra column filled from Finding.Metadata.
+// When groupKey is set, the section is split into one table per value of that metadata key.
+type sourceSection struct {
+	source     string
+	title      string
+	metaColumn string
+	metaKey    string
+	groupKey   string
+}
+
+// sourceSections lists the per-source sections of report.md in rendering order.
+var sourceSections = []sourceSection{
+	{source: "npm", title: "NPM Findings"},
+	{source: "dotnet", title: "Dotnet / NuGet Findings", metaColumn: "Introduced Via", metaKey: "introduced_via"},
+	{source: "bun", title: "Bun / NPM Findings"},
+	{source: "yarn", title: "Yarn / NPM Findings"},
+	{source: "pnpm", title: "pnpm / NPM Findings"},
+	{source: "govulncheck", title: "Go Modules Findings", metaColumn: "Reachability", metaKey: "reachability"},
+	{source: "pip-audit", title: "Python / PyPI Findings"},
+	{source: "jvm", title: "Java / Maven Findings"},
+	{source: "cargo-audit", title: "Rust / Crates.io Findings", metaColumn: "Kind", metaKey: "kind"},
+	{source: "composer", title: "PHP / Packagist Findings", metaColumn: "Kind", metaKey: "kind"},
+	{source: "bundle-audit", title: "Ruby / RubyGems Findings", metaColumn: "Kind", metaKey: "kind"},
+	{source: "osv", title: "OSV Findings"},
+	{source: "osv-offline", title: "OSV Offline Findings"},
+	{source: "trivy", title: "Container / OS Findings", groupKey: "image"},
+	{source: "base-image", title: "Base Image Findings", metaColumn: "Stage", metaKey: "stage"},
+	{source: "compose", title: "Compose Service Findings", metaColumn: "Service", metaKey: "compose_service"},
+	{source: "trivy-fs", title: "Trivy Filesystem Findings", metaColumn: "Type", metaKey: "type"},
+}
+
 func Generate(outDir string, meta ReportMeta, findings []model.Finding) error {
 	if err := os.MkdirAll(outDir, 0755); err != nil {
 		return err
@@ -59,13 +114,31 @@ func Generate(outDir string, meta ReportMeta, findings []model.Finding) error {
 	return nil
 }
 
-func generateMarkdown(meta ReportMeta, findings []model.Finding) string {
+func generateMarkdown(meta ReportMeta, allFindings []model.Finding) string {
 	var sb strings.Builder
 
+	// Misconfigurations and package health findings (deprecated, outdated, ...) get their own sections;
+	// everything else (vulnerabilities, insecure sources) is counted in the summary
+	var findings, misconfigs, health []model.Finding
+	for _, f := range allFindings {
+		switch {
+		case f.IsPackageHealth():
+			health = append(health, f)
+		case f.Class == model.ClassMisconfiguration:
+			misconfigs = append(misconfigs, f)
+		default:
+			findings = append(findings, f)
+		}
+	}
+
 	sb.WriteString(fmt.Sprintf("# DepScanity Report\n\n"))
 	sb.WriteString(fmt.Sprintf("**Target:** `%s`\n", meta.ScannedPath))
 	sb.WriteString(fmt.Sprintf("**Timestamp:** %s\n", meta.Timestamp))
-	sb.WriteString(fmt.Sprintf("**Fail On:** %s\n\n", meta.FailOn))
+	sb.WriteString(fmt.Sprintf("**Fail On:** %s\n", meta.FailOn))
+	for _, db := range meta.Databases {
+		sb.WriteString(fmt.Sprintf("**Database:** %s v%d (updated %s)\n", db.Name, db.Version, db.UpdatedAt))
+	}
+	sb.WriteString("\n")
 
 	// Counts
 	counts := make(map[model.Severity]int)
@@ -123,70 +196,79 @@ func generateMarkdown(meta ReportMeta, findings []model.Finding) string {
 
 	sb.WriteString("\n## Findings by Source\n\n")
 
-	// NPM specific	// Findings by source
-	// NPM
-	var npmFindings []model.Finding
-	for _, f := range findings {
-		if f.Source == "npm" {
-			npmFindings = append(npmFindings, f)
-		}
-	}
-	if len(npmFindings) > 0 {
-		fmt.Fprintf(&sb, "\n## NPM Findings (%d)\n\n", len(npmFindings))
-		fmt.Fprintf(&sb, "| Severity | Package | Version | Vuln ID |\n")
-		fmt.Fprintf(&sb, "|---|---|---|---|\n")
-		for _, f := range npmFindings {
-			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", f.Severity, f.Package, f.InstalledVersion, f.VulnerabilityID)
+	for _, section := range sourceSections {
+		var sectionFindings []model.Finding
+		for _, f := range findings {
+			if f.Source == section.source {
+				sectionFindings = append(sectionFindings, f)
+			}
 		}
+		writeSourceSection(&sb, section, sectionFindings)
 	}
 
-	// Dotnet
-	var dotnetFindings []model.Finding
-	for _, f := range findings {
-		if f.Source == "dotnet" {
-			dotnetFindings = append(dotnetFindings, f)
-		}
-	}
-	if len(dotnetFindings) > 0 {
-		fmt.Fprintf(&sb, "\n## Dotnet / NuGet Findings (%d)\n\n", len(dotnetFindings))
-		fmt.Fprintf(&sb, "| Severity | Package | Version | Vuln ID |\n")
-		fmt.Fprintf(&sb, "|---|---|---|---|\n")
-		for _, f := range dotnetFindings {
-			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", f.Severity, f.Package, f.InstalledVersion, f.VulnerabilityID)
+	// Misconfigurations Section
+	if len(misconfigs) > 0 {
+		fmt.Fprintf(&sb, "\n## Misconfigurations (%d)\n\n", len(misconfigs))
+		fmt.Fprintf(&sb, "| Severity | Check | Title | Resource | Location |\n")
+		fmt.Fprintf(&sb, "|---|---|---|---|---|\n")
+		for _, f := range misconfigs {
+			title := ""
+			if f.Title != nil {
+				title = strings.ReplaceAll(*f.Title, "|", "\\|")
+			}
+			loc := f.Location
+			if rel, err := filepath.Rel(meta.ScannedPath, loc); err == nil && !strings.HasPrefix(rel, "..") {
+				loc = rel
+			}
+			if line, ok := f.Metadata["start_line"]; ok {
+				loc = fmt.Sprintf("%s:%v", loc, line)
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", f.Severity, f.VulnerabilityID, title, f.Package, loc)
 		}
 	}
 
-	// Bun
-	var bunFindings []model.Finding
-	for _, f := range findings {
-		if f.Source == "bun" {
-			bunFindings = append(bunFindings, f)
-		}
-	}
-	if len(bunFindings) > 0 {
-		fmt.Fprintf(&sb, "\n## Bun / NPM Findings (%d)\n\n", len(bunFindings))
-		fmt.Fprintf(&sb, "| Severity | Package | Version | Vuln ID |\n")
-		fmt.Fprintf(&sb, "|---|---|---|---|\n")
-		for _, f := range bunFindings {
-			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", f.Severity, f.Package, f.InstalledVersion, f.VulnerabilityID)
+	// Package Health Section
+	if len(health) > 0 {
+		fmt.Fprintf(&sb, "\n## Package Health (%d)\n\n", len(health))
+		fmt.Fprintf(&sb, "_Deprecated, outdated, abandoned and yanked packages; not counted in the summary above._\n\n")
+		fmt.Fprintf(&sb, "| Class | Package | Version | Details | Location |\n")
+		fmt.Fprintf(&sb, "|---|---|---|---|---|\n")
+		for _, f := range health {
+			details := ""
+			if f.Title != nil {
+				details = strings.ReplaceAll(*f.Title, "|", "\\|")
+			}
+			loc := f.Location
+			if project, ok := f.Metadata["project"].(string); ok && project != "" {
+				loc = project
+			}
+			if rel, err := filepath.Rel(meta.ScannedPath, loc); err == nil && !strings.HasPrefix(rel, "..") {
+				loc = rel
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s |\n", f.Class, f.Package, f.InstalledVersion, details, loc)
 		}
 	}
 
-	// Trivy / Container
-	var containerFindings []model.Finding
-	for _, f := range findings {
-		if f.Source == "trivy" {
-			containerFindings = append(containerFindings, f)
-		}
-	}
-	if len(containerFindings) > 0 {
-		fmt.Fprintf(&sb, "\n## Container / OS Findings (%d)\n\n", len(containerFindings))
-		fmt.Fprintf(&sb, "| Severity | Package | Version | Vuln ID |\n")
-		fmt.Fprintf(&sb, "|---|---|---|---|\n")
-		for _, f := range containerFindings {
-			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", f.Severity, f.Package, f.InstalledVersion, f.VulnerabilityID)
+	// Timings Section
+	if len(meta.Timings) > 0 {
+		fmt.Fprintf(&sb, "\n## Timings\n\n")
+		fmt.Fprintf(&sb, "| Source | Location | Phase | Duration | Status |\n")
+		fmt.Fprintf(&sb, "|---|---|---|---|---|\n")
+		for _, t := range meta.Timings {
+			status := "ok"
+			if t.TimedOut {
+				status = "⏱ timed out"
+			} else if t.ExitCode != 0 {
+				status = fmt.Sprintf("exit %d", t.ExitCode)
+			}
+			loc := t.Location
+			if rel, err := filepath.Rel(meta.ScannedPath, t.Location); err == nil && !strings.HasPrefix(rel, "..") {
+				loc = rel
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %.1fs | %s |\n", t.Source, loc, t.Phase, float64(t.DurationMs)/1000, status)
 		}
 	}
+
 	// Scanner Errors Section
 	if len(meta.ScannerErrors) > 0 {
 		fmt.Fprintf(&sb, "\n## ⚠️ Scanner Errors (%d)\n\n", len(meta.ScannerErrors))
@@ -205,3 +287,82 @@ func generateMarkdown(meta ReportMeta, findings []model.Finding) string {
 
 	return sb.String()
 }
+
+func writeSourceSection(sb *strings.Builder, section sourceSection, findings []model.Finding) {
+	if len(findings) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n## %s (%d)\n", section.title, len(findings))
+	if section.groupKey == "" {
+		sb.WriteString("\n")
+		writeFindingsTable(sb, section, findings)
+		return
+	}
+
+	// One sub-section per group, in order of first appearance
+	var groups []string
+	byGroup := make(map[string][]model.Finding)
+	for _, f := range findings {
+		group := fmt.Sprint(f.Metadata[section.groupKey])
+		if _, ok := byGroup[group]; !ok {
+			groups = append(groups, group)
+		}
+		byGroup[group] = append(byGroup[group], f)
+	}
+	for _, group := range groups {
+		fmt.Fprintf(sb, "\n### `%s` (%d)\n\n", group, len(byGroup[group]))
+		writeFindingsTable(sb, section, byGroup[group])
+	}
+}
+
+// writeFindingsTable writes one findings table. Container findings attributed to image layers
+// get an extra Origin column: "base image", or "added by us" with the Dockerfile line.
+func writeFindingsTable(sb *strings.Builder, section sourceSection, findings []model.Finding) {
+	columns := []string{"Severity", "Package", "Version", "Vuln ID"}
+	if section.metaKey != "" {
+		columns = append(columns, section.metaColumn)
+	}
+	withOrigin := false
+	for _, f := range findings {
+		if _, ok := f.Metadata["layer_origin"]; ok {
+			withOrigin = true
+			break
+		}
+	}
+	if withOrigin {
+		columns = append(columns, "Origin")
+	}
+
+	fmt.Fprintf(sb, "| %s |\n", strings.Join(columns, " | "))
+	fmt.Fprintf(sb, "|%s\n", strings.Repeat("---|", len(columns)))
+	for _, f := range findings {
+		cells := []string{string(f.Severity), f.Package, f.InstalledVersion, f.VulnerabilityID}
+		if section.metaKey != "" {
+			value := ""
+			if v, ok := f.Metadata[section.metaKey]; ok && v != nil {
+				value = strings.ReplaceAll(fmt.Sprint(v), "|", "\\|")
+			}
+			cells = append(cells, value)
+		}
+		if withOrigin {
+			cells = append(cells, layerOrigin(f))
+		}
+		fmt.Fprintf(sb, "| %s |\n", strings.Join(cells, " | "))
+	}
+}
+
+// layerOrigin describes where the layer of a container finding comes from, e.g. "added by us (Dockerfile:12)".
+func layerOrigin(f model.Finding) string {
+	origin, ok := f.Metadata["layer_origin"].(string)
+	if !ok {
+		return ""
+	}
+	if line, ok := f.Metadata["dockerfile_line"]; ok {
+		name := "Dockerfile"
+		if path, ok := f.Metadata["dockerfile"].(string); ok && path != "" {
+			name = filepath.Base(path)
+		}
+		origin = fmt.Sprintf("%s (%s:%v)", origin, name, line)
+	}
+	return origin
+}
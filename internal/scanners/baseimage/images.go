@@ -0,0 +1,53 @@
+package baseimage
+
+import (
+	"strings"
+
+	"depscanity/internal/dockerfile"
+)
+
+// BaseImage is an external image used by one or more stages of a Dockerfile.
+type BaseImage struct {
+	Image  string
+	Stages []string
+}
+
+// BaseImages returns the external images of the stages, in order of first use.
+// scratch and references to earlier stages are skipped; stages whose image could not be
+// resolved (an ARG without default) are returned in unresolved.
+func BaseImages(stages []dockerfile.Stage) (images []BaseImage, unresolved []string) {
+	aliases := make(map[string]bool)
+	index := make(map[string]int)
+	for _, stage := range stages {
+		from := stage.From
+		isStage := aliases[strings.ToLower(from)]
+		if stage.Name != "" {
+			aliases[strings.ToLower(stage.Name)] = true
+		}
+
+		switch {
+		case isStage || strings.EqualFold(from, "scratch"):
+			continue
+		case !resolved(from):
+			unresolved = append(unresolved, stage.Label())
+			continue
+		}
+
+		if i, ok := index[from]; ok {
+			images[i].Stages = append(images[i].Stages, stage.Label())
+			continue
+		}
+		index[from] = len(images)
+		images = append(images, BaseImage{Image: from, Stages: []string{stage.Label()}})
+	}
+	return images, unresolved
+}
+
+// resolved rejects references left incomplete by unset variables ("", "alpine:", "/alpine").
+func resolved(ref string) bool {
+	if ref == "" || strings.Contains(ref, "$") {
+		return false
+	}
+	return !strings.HasPrefix(ref, "/") && !strings.HasPrefix(ref, ":") &&
+		!strings.HasSuffix(ref, ":") && !strings.HasSuffix(ref, "@") && !strings.HasSuffix(ref, "-")
+}
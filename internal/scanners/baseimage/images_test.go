@@ -0,0 +1,35 @@
+package baseimage
+
+import (
+	"reflect"
+	"testing"
+
+	"depscanity/internal/dockerfile"
+)
+
+func TestBaseImages(t *testing.T) {
+	data := []byte(`ARG ALPINE_TAG
+FROM golang:1.22-alpine AS build
+FROM build AS test
+FROM alpine:${ALPINE_TAG:-3.19} AS runtime
+FROM scratch AS minimal
+FROM alpine:${ALPINE_TAG:-3.19}
+FROM ${MISSING}
+`)
+	stages, err := dockerfile.Parse(data, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	images, unresolved := BaseImages(stages)
+	want := []BaseImage{
+		{Image: "golang:1.22-alpine", Stages: []string{"build"}},
+		{Image: "alpine:3.19", Stages: []string{"runtime", "4"}},
+	}
+	if !reflect.DeepEqual(images, want) {
+		t.Errorf("unexpected base images: %+v", images)
+	}
+	if !reflect.DeepEqual(unresolved, []string{"5"}) {
+		t.Errorf("expected FROM ${MISSING} to be unresolved, got %v", unresolved)
+	}
+}
@@ -0,0 +1,108 @@
+package baseimage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/dockerfile"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+	"depscanity/internal/scanners/trivy"
+)
+
+// Scanner scans the base images of Dockerfile FROM lines with trivy image, without building.
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the base image scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "base-image" }
+
+func (s *Scanner) LockGroup(target string) string { return trivy.LockGroup }
+
+// Targets returns one "<Dockerfile>#<stage>" target per distinct base image when --base-images is set.
+// A Dockerfile that cannot be parsed is returned as is so that Scan reports the error.
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	if s.opts.NoContainer || !s.opts.BaseImages {
+		return nil
+	}
+
+	var targets []string
+	for _, path := range det.Docker {
+		if !detect.IsDockerfile(path) {
+			continue
+		}
+		images, unresolved, err := loadBaseImages(path)
+		if err != nil {
+			targets = append(targets, path)
+			continue
+		}
+		for _, stage := range unresolved {
+			fmt.Printf("  [Base Image] Skipping stage %s in %s: FROM uses an ARG without default\n", stage, path)
+		}
+		for _, img := range images {
+			targets = append(targets, path+"#"+img.Stages[0])
+		}
+	}
+	return targets
+}
+
+func (s *Scanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	path, stage, _ := strings.Cut(target, "#")
+	images, _, err := loadBaseImages(path)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "base-image",
+			Location: path,
+			Message:  fmt.Sprintf("failed to parse Dockerfile: %v", err),
+		}}
+	}
+
+	for _, img := range images {
+		if img.Stages[0] == stage {
+			return ScanBaseImage(ctx, path, img, s.opts)
+		}
+	}
+	return nil, []report.ScannerError{{
+		Source:   "base-image",
+		Location: path,
+		Message:  fmt.Sprintf("stage %s not found", stage),
+	}}
+}
+
+// ScanBaseImage scans one base image and marks the findings with the Dockerfile and stages using it.
+func ScanBaseImage(ctx context.Context, dockerfile string, img BaseImage, opts scanners.Options) ([]model.Finding, []report.ScannerError) {
+	findings, scannerErrors := trivy.ScanTrivy(ctx, img.Image, opts)
+	for i := range findings {
+		f := &findings[i]
+		f.Source = "base-image"
+		if f.Metadata == nil {
+			f.Metadata = make(map[string]any)
+		}
+		f.Metadata["dockerfile"] = dockerfile
+		f.Metadata["stage"] = strings.Join(img.Stages, ", ")
+		f.Metadata["base_image"] = img.Image
+	}
+	return findings, scannerErrors
+}
+
+func loadBaseImages(path string) ([]BaseImage, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	stages, err := dockerfile.Parse(data, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	images, unresolved := BaseImages(stages)
+	return images, unresolved, nil
+}
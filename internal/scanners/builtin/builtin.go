@@ -0,0 +1,46 @@
+// Package builtin registers the scanners shipped with DepScanity.
+// Import it for its side effects; additional scanners call scanners.Register from their own init.
+package builtin
+
+import (
+	"depscanity/internal/scanners"
+	"depscanity/internal/scanners/baseimage"
+	"depscanity/internal/scanners/bun"
+	"depscanity/internal/scanners/bundleraudit"
+	"depscanity/internal/scanners/cargoaudit"
+	"depscanity/internal/scanners/compose"
+	"depscanity/internal/scanners/composer"
+	"depscanity/internal/scanners/dotnet"
+	"depscanity/internal/scanners/govulncheck"
+	"depscanity/internal/scanners/jvm"
+	"depscanity/internal/scanners/npm"
+	"depscanity/internal/scanners/osv"
+	"depscanity/internal/scanners/osvoffline"
+	"depscanity/internal/scanners/pipaudit"
+	"depscanity/internal/scanners/pnpm"
+	"depscanity/internal/scanners/trivy"
+	"depscanity/internal/scanners/yarn"
+)
+
+// The scan command runs the scanners in this order: the ecosystem scanners, then the
+// cross-ecosystem OSV scanners, then the container and repository scanners built on trivy.
+func init() {
+	scanners.Register("npm", npm.New)
+	scanners.Register("bun", bun.New)
+	scanners.Register("yarn", yarn.New)
+	scanners.Register("pnpm", pnpm.New)
+	scanners.Register("dotnet", dotnet.New)
+	scanners.Register("govulncheck", govulncheck.New)
+	scanners.Register("pip-audit", pipaudit.New)
+	scanners.Register("jvm", jvm.New)
+	scanners.Register("cargo-audit", cargoaudit.New)
+	scanners.Register("composer", composer.New)
+	scanners.Register("bundle-audit", bundleraudit.New)
+	scanners.Register("osv", osv.New)
+	scanners.Register("osv-offline", osvoffline.New)
+	scanners.Register("trivy", trivy.New)
+	scanners.Register("base-image", baseimage.New)
+	scanners.Register("compose", compose.New)
+	scanners.Register("trivy-fs", trivy.NewFs)
+	scanners.Register("trivy-config", trivy.NewConfig)
+}
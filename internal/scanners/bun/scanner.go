@@ -7,16 +7,52 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
-	depExec "depscanity/internal/exec"
+	"depscanity/internal/detect"
 	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
 )
 
+// MaxLockfiles caps how many bun.lock files are audited per run.
+const MaxLockfiles = 10
+
+// Scanner audits bun.lock files with bun audit.
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the bun scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "bun" }
+
+func (s *Scanner) MaxTargets() int { return MaxLockfiles }
+
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	return det.Bun
+}
+
+func (s *Scanner) Scan(ctx context.Context, lockPath string) ([]model.Finding, []report.ScannerError) {
+	findings, err := ScanBun(ctx, lockPath, s.opts)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "bun",
+			Location: lockPath,
+			Message:  err.Error(),
+		}}
+	}
+	return findings, nil
+}
+
 // ScanBun executes bun audit and parses the results.
-func ScanBun(ctx context.Context, lockPath string, timeoutSec int, outDir string) ([]model.Finding, error) {
+func ScanBun(ctx context.Context, lockPath string, opts scanners.Options) ([]model.Finding, error) {
 	// 1. Setup paths
 	workDir := filepath.Dir(lockPath)
-	rawOutDir := filepath.Join(outDir, "raw")
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
 	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create raw output dir: %w", err)
 	}
@@ -32,11 +68,14 @@ func ScanBun(ctx context.Context, lockPath string, timeoutSec int, outDir string
 
 	args := []string{"audit", "--json"}
 
-	res, err := depExec.Run(ctx, "bun", args, workDir)
+	res, err := opts.RunPhase(ctx, "bun", scanners.PhaseAudit, lockPath, "bun", args, workDir)
 
 	// bun audit returns non-zero exit code if vulnerabilities are found?
 	// Verified behavior: exit code 1 if vulnerabilities found.
-	if res.ExitCode == 127 || res.ExitCode == 124 {
+	if res.ExitCode == 124 {
+		return nil, fmt.Errorf("bun audit timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit))
+	}
+	if res.ExitCode == 127 {
 		return nil, fmt.Errorf("bun audit failed execution (code %d): %v", res.ExitCode, err)
 	}
 
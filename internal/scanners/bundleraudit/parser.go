@@ -0,0 +1,243 @@
+package bundleraudit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"depscanity/internal/model"
+	"depscanity/internal/scanners/osv"
+)
+
+// Finding kinds stored in Metadata["kind"].
+const (
+	KindVulnerability  = "vulnerability"
+	KindInsecureSource = "insecure_source"
+)
+
+// Report is the document written by `bundle-audit check --format json`.
+type Report struct {
+	Version string   `json:"version"`
+	Results []Result `json:"results"`
+}
+
+// Result is an unpatched gem or an insecure gem source.
+type Result struct {
+	Type string `json:"type"`
+	Gem  struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"gem"`
+	Advisory *Advisory `json:"advisory"`
+	// insecure_source only
+	Source string `json:"source"`
+}
+
+// Advisory is a ruby-advisory-db entry.
+type Advisory struct {
+	ID                 string   `json:"id"`
+	URL                string   `json:"url"`
+	Title              string   `json:"title"`
+	Date               string   `json:"date"`
+	Description        string   `json:"description"`
+	CVSSv2             *float64 `json:"cvss_v2"`
+	CVSSv3             *float64 `json:"cvss_v3"`
+	CVE                string   `json:"cve"`
+	GHSA               string   `json:"ghsa"`
+	Criticality        string   `json:"criticality"`
+	UnaffectedVersions []string `json:"unaffected_versions"`
+	PatchedVersions    []string `json:"patched_versions"`
+}
+
+// ParseBundleAuditOutput parses bundle-audit JSON output into findings.
+func ParseBundleAuditOutput(output string, lockPath string) ([]model.Finding, error) {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil, nil
+	}
+
+	var rep Report
+	if err := json.Unmarshal([]byte(output), &rep); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bundle-audit json: %w", err)
+	}
+
+	var findings []model.Finding
+	for _, r := range rep.Results {
+		switch r.Type {
+		case "unpatched_gem":
+			if r.Advisory == nil {
+				continue
+			}
+			findings = append(findings, newVulnerability(r, lockPath))
+		case "insecure_source":
+			// Gems fetched over plain HTTP or git:// can be tampered with in transit
+			title := fmt.Sprintf("Gems are fetched over an insecure source: %s", r.Source)
+			findings = append(findings, model.Finding{
+				Source:          "bundle-audit",
+				Ecosystem:       "rubygems",
+				Package:         r.Source,
+				VulnerabilityID: "insecure-source:" + r.Source,
+				Severity:        model.SeverityHigh,
+				Title:           &title,
+				Location:        lockPath,
+				Class:           model.ClassInsecureSource,
+				Metadata: map[string]any{
+					"kind": KindInsecureSource,
+				},
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+func newVulnerability(r Result, lockPath string) model.Finding {
+	a := r.Advisory
+
+	f := model.Finding{
+		Source:           "bundle-audit",
+		Ecosystem:        "rubygems",
+		Package:          r.Gem.Name,
+		InstalledVersion: r.Gem.Version,
+		VulnerabilityID:  advisoryID(a),
+		Severity:         severity(a),
+		Location:         lockPath,
+		Metadata: map[string]any{
+			"kind": KindVulnerability,
+		},
+	}
+
+	if a.Title != "" {
+		title := a.Title
+		f.Title = &title
+	}
+	if a.URL != "" {
+		url := a.URL
+		f.URL = &url
+	}
+	if len(a.PatchedVersions) > 0 {
+		if fixed := FixedVersion(r.Gem.Version, a.PatchedVersions); fixed != "" {
+			f.FixedVersion = &fixed
+		}
+		f.Metadata["patched_versions"] = a.PatchedVersions
+	}
+	if a.Description != "" {
+		f.Metadata["description"] = a.Description
+	}
+	if a.Date != "" {
+		f.Metadata["date"] = a.Date
+	}
+
+	var aliases []string
+	if a.CVE != "" {
+		aliases = append(aliases, "CVE-"+a.CVE)
+	}
+	if a.GHSA != "" {
+		aliases = append(aliases, "GHSA-"+a.GHSA)
+	}
+	if len(aliases) > 0 {
+		f.Metadata["aliases"] = aliases
+	}
+
+	return f
+}
+
+// advisoryID prefers the CVE, then the GHSA identifier, then the ruby-advisory-db ID.
+func advisoryID(a *Advisory) string {
+	switch {
+	case a.CVE != "":
+		return "CVE-" + a.CVE
+	case a.GHSA != "":
+		return "GHSA-" + a.GHSA
+	default:
+		return a.ID
+	}
+}
+
+// severity uses the advisory criticality, falling back to the CVSS v3 then v2 score.
+func severity(a *Advisory) model.Severity {
+	if sev, err := model.ParseSeverity(a.Criticality); err == nil {
+		return sev
+	}
+	if a.CVSSv3 != nil {
+		return osv.SeverityFromScore(*a.CVSSv3)
+	}
+	if a.CVSSv2 != nil {
+		return osv.SeverityFromScore(*a.CVSSv2)
+	}
+	return model.SeverityUnknown
+}
+
+// FixedVersion picks the patched requirement that applies to the installed version.
+// Requirements look like "~> 3.2.22", ">= 4.2.5.1, < 5.0" or ">= 5.0.0.beta1"; the
+// lowest bound above the installed version wins.
+func FixedVersion(installed string, patched []string) string {
+	best := ""
+	for _, req := range patched {
+		for _, clause := range strings.Split(req, ",") {
+			clause = strings.TrimSpace(clause)
+			var v string
+			switch {
+			case strings.HasPrefix(clause, "~>"):
+				v = strings.TrimSpace(strings.TrimPrefix(clause, "~>"))
+			case strings.HasPrefix(clause, ">="):
+				v = strings.TrimSpace(strings.TrimPrefix(clause, ">="))
+			default:
+				continue
+			}
+			if compareGemVersions(v, installed) <= 0 {
+				continue
+			}
+			if best == "" || compareGemVersions(v, best) < 0 {
+				best = v
+			}
+		}
+	}
+	return best
+}
+
+// compareGemVersions compares dotted versions segment by segment.
+// Numeric segments compare numerically; a string segment (prerelease) sorts before a number.
+func compareGemVersions(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if x == y {
+			continue
+		}
+		xn, xErr := strconv.Atoi(x)
+		yn, yErr := strconv.Atoi(y)
+		switch {
+		case x == "":
+			if yErr != nil {
+				return 1
+			}
+			return -1
+		case y == "":
+			if xErr != nil {
+				return -1
+			}
+			return 1
+		case xErr == nil && yErr == nil:
+			if xn < yn {
+				return -1
+			}
+			return 1
+		case xErr == nil:
+			return 1
+		case yErr == nil:
+			return -1
+		default:
+			return strings.Compare(x, y)
+		}
+	}
+	return 0
+}
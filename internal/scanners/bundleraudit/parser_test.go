@@ -0,0 +1,62 @@
+package bundleraudit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"depscanity/internal/model"
+)
+
+func TestParseBundleAuditOutput(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "bundle_audit_output.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParseBundleAuditOutput(string(data), "/repo/Gemfile.lock")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %d", len(findings))
+	}
+
+	source := findings[0]
+	if source.Metadata["kind"] != KindInsecureSource || source.Severity != model.SeverityHigh || source.IsPackageHealth() {
+		t.Errorf("unexpected insecure source finding: %v %s %q", source.Metadata["kind"], source.Severity, source.Class)
+	}
+
+	actionpack := findings[1]
+	if actionpack.Ecosystem != "rubygems" || actionpack.Package != "actionpack" || actionpack.VulnerabilityID != "CVE-2016-2098" {
+		t.Errorf("unexpected identity: %s %s %s", actionpack.Ecosystem, actionpack.Package, actionpack.VulnerabilityID)
+	}
+	if actionpack.Severity != model.SeverityHigh {
+		t.Errorf("expected high criticality, got %s", actionpack.Severity)
+	}
+	if actionpack.FixedVersion == nil || *actionpack.FixedVersion != "4.2.5.2" {
+		t.Errorf("expected fixed version 4.2.5.2, got %v", actionpack.FixedVersion)
+	}
+
+	nokogiri := findings[2]
+	if nokogiri.VulnerabilityID != "GHSA-vr8q-g5c7-m54m" {
+		t.Errorf("expected GHSA ID, got %s", nokogiri.VulnerabilityID)
+	}
+	if nokogiri.Severity != model.SeverityLow {
+		t.Errorf("expected low severity from CVSS v3 2.6, got %s", nokogiri.Severity)
+	}
+	if nokogiri.FixedVersion == nil || *nokogiri.FixedVersion != "1.11.0.rc4" {
+		t.Errorf("expected fixed version 1.11.0.rc4, got %v", nokogiri.FixedVersion)
+	}
+}
+
+func TestFixedVersion(t *testing.T) {
+	patched := []string{"~> 5.2.4, >= 5.2.4.3", ">= 6.0.3.1"}
+	if got := FixedVersion("5.2.4.1", patched); got != "5.2.4.3" {
+		t.Errorf("expected 5.2.4.3, got %s", got)
+	}
+	if got := FixedVersion("6.0.0", patched); got != "6.0.3.1" {
+		t.Errorf("expected 6.0.3.1, got %s", got)
+	}
+}
@@ -0,0 +1,109 @@
+package bundleraudit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+)
+
+// MaxLockfiles caps how many Gemfile.lock files are audited per run.
+const MaxLockfiles = 10
+
+// Scanner audits Gemfile.lock files with bundler-audit.
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the bundle-audit scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "bundle-audit" }
+
+func (s *Scanner) MaxTargets() int { return MaxLockfiles }
+
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	return det.Ruby
+}
+
+func (s *Scanner) Scan(ctx context.Context, lockPath string) ([]model.Finding, []report.ScannerError) {
+	findings, err := ScanBundleAudit(ctx, lockPath, s.opts)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "bundle-audit",
+			Location: lockPath,
+			Message:  err.Error(),
+		}}
+	}
+	return findings, nil
+}
+
+// ScanBundleAudit executes bundle-audit check against a Gemfile.lock and parses the results.
+// With --ruby-advisory-db the given checkout is used instead of the user's default database.
+func ScanBundleAudit(ctx context.Context, lockPath string, opts scanners.Options) ([]model.Finding, error) {
+	// 1. Setup paths
+	workDir := filepath.Dir(lockPath)
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
+	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create raw output dir: %w", err)
+	}
+
+	// 2. Check bundle-audit existence
+	if _, err := exec.LookPath("bundle-audit"); err != nil {
+		return nil, fmt.Errorf("bundle-audit executable not found in PATH (gem install bundler-audit)")
+	}
+
+	// 3. Execution
+	args := []string{"check", workDir, "--gemfile-lock", filepath.Base(lockPath), "--format", "json", "--quiet"}
+	if opts.RubyDB != "" {
+		args = append(args, "--database", opts.RubyDB)
+	}
+
+	res, err := opts.RunPhase(ctx, "bundle-audit", scanners.PhaseAudit, lockPath, "bundle-audit", args, workDir)
+	// bundle-audit exits 1 when vulnerabilities are found
+	if res.ExitCode == 124 {
+		return nil, fmt.Errorf("bundle-audit timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit))
+	}
+	if res.ExitCode == 127 {
+		return nil, fmt.Errorf("bundle-audit failed execution (code %d): %v", res.ExitCode, err)
+	}
+
+	// 4. Save raw output
+	sanitizedName := sanitizePath(workDir)
+	rawFile := filepath.Join(rawOutDir, fmt.Sprintf("bundle-audit-%s.json", sanitizedName))
+	if err := os.WriteFile(rawFile, []byte(res.Stdout), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write raw output: %w", err)
+	}
+
+	if res.ExitCode != 0 && strings.TrimSpace(res.Stdout) == "" {
+		return nil, fmt.Errorf("bundle-audit failed (code %d): %v\nStderr: %s", res.ExitCode, err, res.Stderr)
+	}
+
+	// 5. Parse
+	findings, err := ParseBundleAuditOutput(res.Stdout, lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	return findings, nil
+}
+
+func sanitizePath(path string) string {
+	s := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, path)
+	return strings.Trim(s, "_")
+}
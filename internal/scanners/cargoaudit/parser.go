@@ -0,0 +1,193 @@
+package cargoaudit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"depscanity/internal/model"
+	"depscanity/internal/scanners/osv"
+)
+
+// Finding kinds stored in Metadata["kind"].
+const (
+	KindVulnerability = "vulnerability"
+	KindUnmaintained  = "unmaintained"
+	KindUnsound       = "unsound"
+	KindYanked        = "yanked"
+	KindNotice        = "notice"
+)
+
+// Report is the document written by `cargo audit --json`.
+type Report struct {
+	Vulnerabilities struct {
+		Found bool    `json:"found"`
+		Count int     `json:"count"`
+		List  []Entry `json:"list"`
+	} `json:"vulnerabilities"`
+	// Warnings are keyed by kind (unmaintained, unsound, yanked, notice)
+	Warnings map[string][]Entry `json:"warnings"`
+}
+
+// Entry is a vulnerability or warning for one locked crate.
+type Entry struct {
+	Kind     string    `json:"kind"`
+	Advisory *Advisory `json:"advisory"`
+	Versions *struct {
+		Patched    []string `json:"patched"`
+		Unaffected []string `json:"unaffected"`
+	} `json:"versions"`
+	Package struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Source  string `json:"source"`
+	} `json:"package"`
+}
+
+// Advisory is a RustSec advisory.
+type Advisory struct {
+	ID            string   `json:"id"`
+	Package       string   `json:"package"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Date          string   `json:"date"`
+	Aliases       []string `json:"aliases"`
+	Categories    []string `json:"categories"`
+	CVSS          string   `json:"cvss"`
+	Informational string   `json:"informational"`
+	URL           string   `json:"url"`
+	Withdrawn     string   `json:"withdrawn"`
+}
+
+// ParseCargoAuditOutput parses `cargo audit --json` output into findings.
+// Vulnerabilities and warnings (unmaintained, unsound, yanked) are both reported;
+// Metadata["kind"] tells them apart. Unmaintained and yanked crates are package health
+// findings (see warningClass); unsound and notice warnings stay vulnerabilities without severity.
+func ParseCargoAuditOutput(output string, lockPath string) ([]model.Finding, error) {
+	if strings.TrimSpace(output) == "" {
+		return nil, nil
+	}
+
+	var rep Report
+	if err := json.Unmarshal([]byte(output), &rep); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cargo audit json: %w", err)
+	}
+
+	var findings []model.Finding
+	for _, e := range rep.Vulnerabilities.List {
+		findings = append(findings, newFinding(e, KindVulnerability, lockPath))
+	}
+
+	// Map iteration order is random; keep warnings deterministic
+	kinds := make([]string, 0, len(rep.Warnings))
+	for kind := range rep.Warnings {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		for _, e := range rep.Warnings[kind] {
+			entryKind := kind
+			if e.Kind != "" {
+				entryKind = e.Kind
+			}
+			findings = append(findings, newFinding(e, entryKind, lockPath))
+		}
+	}
+
+	return findings, nil
+}
+
+func newFinding(e Entry, kind string, lockPath string) model.Finding {
+	f := model.Finding{
+		Source:           "cargo-audit",
+		Ecosystem:        "crates.io",
+		Package:          e.Package.Name,
+		InstalledVersion: e.Package.Version,
+		Severity:         model.SeverityUnknown,
+		Location:         lockPath,
+		Class:            warningClass(kind),
+		Metadata: map[string]any{
+			"kind": kind,
+		},
+	}
+	if f.IsPackageHealth() {
+		// Same policy as abandoned Composer packages
+		f.Severity = model.SeverityMedium
+	}
+
+	if e.Advisory == nil {
+		// Yanked crates have no advisory
+		f.VulnerabilityID = fmt.Sprintf("%s:%s@%s", kind, e.Package.Name, e.Package.Version)
+		title := fmt.Sprintf("%s %s is %s", e.Package.Name, e.Package.Version, kind)
+		f.Title = &title
+		return f
+	}
+
+	a := e.Advisory
+	f.VulnerabilityID = a.ID
+	if a.Title != "" {
+		title := a.Title
+		f.Title = &title
+	}
+
+	url := a.URL
+	if url == "" {
+		url = "https://rustsec.org/advisories/" + a.ID
+	}
+	f.URL = &url
+
+	if kind == KindVulnerability && a.CVSS != "" {
+		if score, ok := osv.CVSSv3BaseScore(a.CVSS); ok {
+			f.Severity = osv.SeverityFromScore(score)
+			f.Metadata["cvss_score"] = score
+		}
+		f.Metadata["cvss"] = a.CVSS
+	}
+
+	if e.Versions != nil && len(e.Versions.Patched) > 0 {
+		if fixed := lowerBound(e.Versions.Patched[0]); fixed != "" {
+			f.FixedVersion = &fixed
+		}
+		f.Metadata["patched"] = e.Versions.Patched
+	}
+
+	if len(a.Aliases) > 0 {
+		f.Metadata["aliases"] = a.Aliases
+	}
+	if len(a.Categories) > 0 {
+		f.Metadata["categories"] = a.Categories
+	}
+	if a.Informational != "" {
+		f.Metadata["informational"] = a.Informational
+	}
+	if a.Description != "" {
+		f.Metadata["description"] = a.Description
+	}
+
+	return f
+}
+
+// lowerBound extracts the version from a patched requirement such as ">=0.2.23" or "^1.4.1".
+// Compound requirements ("^0.9.1, >=0.9.4") use their first version.
+func lowerBound(req string) string {
+	req = strings.TrimSpace(strings.Split(req, ",")[0])
+	req = strings.TrimLeft(req, ">=^~ ")
+	if req == "" || strings.ContainsAny(req, "<*") {
+		return ""
+	}
+	return req
+}
+
+// warningClass maps a warning kind to its finding class. Unsound code is a soundness bug in
+// the crate itself (memory safety), so it stays a vulnerability like notices do.
+func warningClass(kind string) model.FindingClass {
+	switch kind {
+	case KindUnmaintained:
+		return model.ClassAbandoned
+	case KindYanked:
+		return model.ClassYanked
+	default:
+		return model.ClassVulnerability
+	}
+}
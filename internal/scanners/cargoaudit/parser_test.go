@@ -0,0 +1,62 @@
+package cargoaudit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"depscanity/internal/model"
+)
+
+func TestParseCargoAuditOutput(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "cargo_audit_output.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParseCargoAuditOutput(string(data), "/repo/Cargo.lock")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings (1 vulnerability, 2 warnings), got %d", len(findings))
+	}
+
+	vuln := findings[0]
+	if vuln.Source != "cargo-audit" || vuln.Ecosystem != "crates.io" || vuln.Package != "time" {
+		t.Errorf("unexpected identity: %s/%s %s", vuln.Source, vuln.Ecosystem, vuln.Package)
+	}
+	if vuln.Metadata["kind"] != KindVulnerability {
+		t.Errorf("expected vulnerability kind, got %v", vuln.Metadata["kind"])
+	}
+	if vuln.Severity != model.SeverityMedium {
+		t.Errorf("expected medium severity from CVSS 5.9, got %s", vuln.Severity)
+	}
+	if vuln.FixedVersion == nil || *vuln.FixedVersion != "0.2.23" {
+		t.Errorf("expected fixed version 0.2.23, got %v", vuln.FixedVersion)
+	}
+	if vuln.URL == nil || *vuln.URL != "https://rustsec.org/advisories/RUSTSEC-2020-0071" {
+		t.Errorf("expected rustsec.org fallback URL, got %v", vuln.URL)
+	}
+
+	// Warnings are sorted by kind: unmaintained before yanked
+	unmaintained := findings[1]
+	if unmaintained.Metadata["kind"] != KindUnmaintained || unmaintained.VulnerabilityID != "RUSTSEC-2021-0139" {
+		t.Errorf("unexpected unmaintained warning: %v %s", unmaintained.Metadata["kind"], unmaintained.VulnerabilityID)
+	}
+	if unmaintained.Class != model.ClassAbandoned || unmaintained.Severity != model.SeverityMedium {
+		t.Errorf("expected abandoned package health finding, got %q %s", unmaintained.Class, unmaintained.Severity)
+	}
+
+	yanked := findings[2]
+	if yanked.Metadata["kind"] != KindYanked || yanked.VulnerabilityID != "yanked:tokio@1.18.4" {
+		t.Errorf("unexpected yanked warning: %v %s", yanked.Metadata["kind"], yanked.VulnerabilityID)
+	}
+	if yanked.Class != model.ClassYanked || !yanked.IsPackageHealth() {
+		t.Errorf("expected yanked package health finding, got %q", yanked.Class)
+	}
+	if !vuln.IsVulnerability() || warningClass(KindUnsound) != model.ClassVulnerability {
+		t.Error("expected vulnerabilities and unsound warnings to stay vulnerabilities")
+	}
+}
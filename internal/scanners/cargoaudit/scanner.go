@@ -0,0 +1,109 @@
+package cargoaudit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+)
+
+// MaxLockfiles caps how many Cargo.lock files are audited per run.
+const MaxLockfiles = 10
+
+// Scanner audits Cargo.lock files with cargo audit.
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the cargo-audit scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "cargo-audit" }
+
+func (s *Scanner) MaxTargets() int { return MaxLockfiles }
+
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	return det.Rust
+}
+
+func (s *Scanner) Scan(ctx context.Context, lockPath string) ([]model.Finding, []report.ScannerError) {
+	findings, err := ScanCargoAudit(ctx, lockPath, s.opts)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "cargo-audit",
+			Location: lockPath,
+			Message:  err.Error(),
+		}}
+	}
+	return findings, nil
+}
+
+// ScanCargoAudit executes cargo audit --json against a Cargo.lock and parses the results.
+// With --rustsec-db the local advisory-db checkout is used and never fetched.
+func ScanCargoAudit(ctx context.Context, lockPath string, opts scanners.Options) ([]model.Finding, error) {
+	// 1. Setup paths
+	workDir := filepath.Dir(lockPath)
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
+	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create raw output dir: %w", err)
+	}
+
+	// 2. Check cargo-audit existence (installed as a cargo subcommand)
+	if _, err := exec.LookPath("cargo-audit"); err != nil {
+		return nil, fmt.Errorf("cargo-audit executable not found in PATH (cargo install cargo-audit)")
+	}
+
+	// 3. Execution
+	args := []string{"audit", "--json", "--file", lockPath}
+	if opts.RustsecDB != "" {
+		args = append(args, "--db", opts.RustsecDB, "--no-fetch", "--stale")
+	}
+
+	res, err := opts.RunPhase(ctx, "cargo-audit", scanners.PhaseAudit, lockPath, "cargo", args, workDir)
+	// cargo audit exits 1 when vulnerabilities are found
+	if res.ExitCode == 124 {
+		return nil, fmt.Errorf("cargo audit timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit))
+	}
+	if res.ExitCode == 127 {
+		return nil, fmt.Errorf("cargo audit failed execution (code %d): %v", res.ExitCode, err)
+	}
+
+	// 4. Save raw output
+	sanitizedName := sanitizePath(workDir)
+	rawFile := filepath.Join(rawOutDir, fmt.Sprintf("cargo-audit-%s.json", sanitizedName))
+	if err := os.WriteFile(rawFile, []byte(res.Stdout), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write raw output: %w", err)
+	}
+
+	if res.ExitCode != 0 && strings.TrimSpace(res.Stdout) == "" {
+		return nil, fmt.Errorf("cargo audit failed (code %d): %v\nStderr: %s", res.ExitCode, err, res.Stderr)
+	}
+
+	// 5. Parse
+	findings, err := ParseCargoAuditOutput(res.Stdout, lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	return findings, nil
+}
+
+func sanitizePath(path string) string {
+	s := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, path)
+	return strings.Trim(s, "_")
+}
@@ -0,0 +1,308 @@
+package compose
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"depscanity/internal/scanners/trivy"
+)
+
+// Project is a parsed compose file.
+type Project struct {
+	File     string
+	Name     string
+	Services []Service
+}
+
+// Service is a compose service with its interpolated image and build settings.
+type Service struct {
+	Name  string
+	Image string
+	// Build is nil when the service has no build: section; Build.Context is absolute.
+	Build    *trivy.BuildSpec
+	Profiles []string
+}
+
+// Env looks up a variable for interpolation.
+type Env func(name string) (string, bool)
+
+// IsComposeFile reports whether path names a compose file
+// (docker-compose.yml|yaml or compose.yml|yaml).
+func IsComposeFile(path string) bool {
+	switch strings.ToLower(filepath.Base(path)) {
+	case "docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml":
+		return true
+	}
+	return false
+}
+
+// LoadProject reads a compose file. Variables are taken from the environment first,
+// then from the .env file next to the compose file, as docker compose does.
+func LoadProject(path string) (*Project, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dotenv, err := readDotEnv(filepath.Join(filepath.Dir(path), ".env"))
+	if err != nil {
+		return nil, err
+	}
+	env := func(name string) (string, bool) {
+		if v, ok := os.LookupEnv(name); ok {
+			return v, true
+		}
+		v, ok := dotenv[name]
+		return v, ok
+	}
+	return ParseProject(data, path, env)
+}
+
+// ParseProject parses the services of a compose file, interpolating ${VAR},
+// ${VAR:-default}, ${VAR-default}, ${VAR:+alt} and $VAR with env.
+func ParseProject(data []byte, path string, env Env) (*Project, error) {
+	root, err := parseYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	expand := func(s string) string { return interpolate(s, env) }
+
+	dir := filepath.Dir(path)
+	project := &Project{File: path, Name: projectName(root, dir, env, expand)}
+
+	services := root.get("services")
+	if services == nil || services.kind != mapNode {
+		return project, nil
+	}
+	for _, name := range services.keys {
+		def := services.fields[name]
+		svc := Service{
+			Name:  name,
+			Image: expand(def.get("image").str()),
+		}
+		for _, profile := range def.get("profiles").list() {
+			svc.Profiles = append(svc.Profiles, expand(profile))
+		}
+		if build := def.get("build"); build != nil {
+			svc.Build = parseBuild(build, dir, expand)
+		}
+		project.Services = append(project.Services, svc)
+	}
+	return project, nil
+}
+
+// parseBuild reads build: as a context path or as a mapping with context, dockerfile, target and args.
+func parseBuild(build *node, dir string, expand func(string) string) *trivy.BuildSpec {
+	spec := &trivy.BuildSpec{Context: expand(build.str())}
+	if build.kind == mapNode {
+		spec.Context = expand(build.get("context").str())
+		spec.Dockerfile = expand(build.get("dockerfile").str())
+		spec.Target = expand(build.get("target").str())
+		spec.Args = buildArgs(build.get("args"), expand)
+	}
+	if spec.Context == "" {
+		spec.Context = "."
+	}
+	if !filepath.IsAbs(spec.Context) {
+		spec.Context = filepath.Join(dir, spec.Context)
+	}
+	return spec
+}
+
+// buildArgs accepts both the mapping form and the ["KEY=value"] list form.
+// Arguments without a value are left to the Dockerfile default.
+func buildArgs(args *node, expand func(string) string) map[string]string {
+	if args == nil {
+		return nil
+	}
+	result := make(map[string]string)
+	switch args.kind {
+	case mapNode:
+		for _, key := range args.keys {
+			if value := args.fields[key]; value.kind == scalarNode && value.value != "" {
+				result[key] = expand(value.value)
+			}
+		}
+	case seqNode:
+		for _, item := range args.list() {
+			if key, value, ok := strings.Cut(item, "="); ok {
+				result[key] = expand(value)
+			}
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+var invalidProjectChars = regexp.MustCompile(`[^a-z0-9_-]`)
+
+// projectName follows docker compose: COMPOSE_PROJECT_NAME, then the top-level name:, then the directory name.
+func projectName(root *node, dir string, env Env, expand func(string) string) string {
+	name, _ := env("COMPOSE_PROJECT_NAME")
+	if name == "" {
+		name = expand(root.get("name").str())
+	}
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+	return sanitizeName(name)
+}
+
+// sanitizeName lower-cases a project or service name and drops the characters docker compose
+// does not allow in project names, which keeps it valid inside an image tag.
+func sanitizeName(name string) string {
+	return invalidProjectChars.ReplaceAllString(strings.ToLower(name), "")
+}
+
+// Enabled reports whether the service runs with the given active profiles.
+// Services without profiles always run; "*" activates every profile.
+func (s Service) Enabled(active []string) bool {
+	if len(s.Profiles) == 0 {
+		return true
+	}
+	for _, a := range active {
+		if a == "*" {
+			return true
+		}
+		for _, p := range s.Profiles {
+			if p == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ImageTarget is one image to scan and the compose services that use it.
+type ImageTarget struct {
+	// Image is the reference to scan: the service image, or the local tag to build when Build is set.
+	Image    string
+	Build    *trivy.BuildSpec
+	Services []string
+}
+
+// ImageTargets groups the enabled services by image. With build set, services that have a
+// build: section are built as depscanity-<project>-<service>:local; otherwise build-only
+// services are returned in skipped.
+func (p *Project) ImageTargets(active []string, build bool) (targets []ImageTarget, skipped []string) {
+	index := make(map[string]int)
+	for _, svc := range p.Services {
+		if !svc.Enabled(active) {
+			continue
+		}
+
+		target := ImageTarget{Image: svc.Image, Services: []string{svc.Name}}
+		if build && svc.Build != nil {
+			target.Image = fmt.Sprintf("depscanity-%s-%s:local", p.Name, sanitizeName(svc.Name))
+			target.Build = svc.Build
+		}
+		if target.Image == "" {
+			skipped = append(skipped, svc.Name)
+			continue
+		}
+
+		if i, ok := index[target.Image]; ok {
+			targets[i].Services = append(targets[i].Services, svc.Name)
+			continue
+		}
+		index[target.Image] = len(targets)
+		targets = append(targets, target)
+	}
+	return targets, skipped
+}
+
+var interpolation = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?[-+?])([^}]*))?\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// interpolate substitutes variables the way docker compose does. "$$" is a literal "$";
+// ${VAR:?err} and ${VAR?err} expand to the value (or "") instead of failing.
+func interpolate(s string, env Env) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+	return interpolation.ReplaceAllStringFunc(s, func(match string) string {
+		if match == "$$" {
+			return "$"
+		}
+		m := interpolation.FindStringSubmatch(match)
+		name, op, arg := m[1], m[2], m[3]
+		if name == "" {
+			name = m[4]
+		}
+		value, set := env(name)
+
+		switch op {
+		case ":-":
+			if value == "" {
+				return arg
+			}
+		case "-":
+			if !set {
+				return arg
+			}
+		case ":+":
+			if value != "" {
+				return arg
+			}
+			return ""
+		case "+":
+			if set {
+				return arg
+			}
+			return ""
+		}
+		return value
+	})
+}
+
+// readDotEnv parses KEY=VALUE lines of a .env file; a missing file is not an error.
+func readDotEnv(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+	return vars, scanner.Err()
+}
+
+// ActiveProfiles returns the profiles from --compose-profile, falling back to COMPOSE_PROFILES.
+func ActiveProfiles(flagged []string) []string {
+	if len(flagged) > 0 {
+		return flagged
+	}
+	var profiles []string
+	for _, p := range strings.Split(os.Getenv("COMPOSE_PROFILES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles
+}
@@ -0,0 +1,153 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"depscanity/internal/scanners/trivy"
+)
+
+func loadShop(t *testing.T, extra map[string]string) *Project {
+	t.Helper()
+	path := filepath.Join("testdata", "shop", "docker-compose.yml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dotenv, err := readDotEnv(filepath.Join("testdata", "shop", ".env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := func(name string) (string, bool) {
+		if v, ok := extra[name]; ok {
+			return v, true
+		}
+		v, ok := dotenv[name]
+		return v, ok
+	}
+	project, err := ParseProject(data, path, env)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return project
+}
+
+func TestParseProject(t *testing.T) {
+	project := loadShop(t, nil)
+
+	if project.Name != "shop" {
+		t.Errorf("expected project name shop, got %q", project.Name)
+	}
+	if len(project.Services) != 6 {
+		t.Fatalf("expected 6 services, got %d", len(project.Services))
+	}
+
+	web := project.Services[0]
+	if web.Name != "web" || web.Image != "ghcr.io/acme/shop-web:1.4.2" {
+		t.Errorf("unexpected web service: %s %s", web.Name, web.Image)
+	}
+	if web.Build == nil || web.Build.Dockerfile != "Dockerfile.prod" || web.Build.Target != "runtime" {
+		t.Fatalf("unexpected web build: %+v", web.Build)
+	}
+	if web.Build.Context != filepath.Join("testdata", "shop", "web") {
+		t.Errorf("expected context next to the compose file, got %s", web.Build.Context)
+	}
+	if !reflect.DeepEqual(web.Build.Args, map[string]string{"NODE_VERSION": "20"}) {
+		t.Errorf("unexpected build args: %v", web.Build.Args)
+	}
+
+	worker := project.Services[1]
+	if worker.Image != "" || worker.Build == nil || worker.Build.Context != filepath.Join("testdata", "shop", "worker") {
+		t.Errorf("unexpected worker service: %q %+v", worker.Image, worker.Build)
+	}
+
+	// Merge keys pull the image from the x-defaults anchor
+	if project.Services[2].Image != "redis:7.2" || project.Services[3].Image != "redis:7.2" {
+		t.Errorf("expected merged redis image, got %q / %q", project.Services[2].Image, project.Services[3].Image)
+	}
+
+	db, debug := project.Services[4], project.Services[5]
+	if db.Image != "postgres:16" || !reflect.DeepEqual(db.Profiles, []string{"db"}) {
+		t.Errorf("unexpected db service: %s %v", db.Image, db.Profiles)
+	}
+	if debug.Image != "busybox:${LITERAL}" || !reflect.DeepEqual(debug.Profiles, []string{"debug", "tools"}) {
+		t.Errorf("unexpected debug service: %s %v", debug.Image, debug.Profiles)
+	}
+}
+
+func TestImageTargets(t *testing.T) {
+	project := loadShop(t, map[string]string{"REDIS_TAG": "", "COMPOSE_PROJECT_NAME": "Ci"})
+
+	targets, skipped := project.ImageTargets(nil, false)
+	if !reflect.DeepEqual(skipped, []string{"worker"}) {
+		t.Errorf("expected build-only worker to be skipped, got %v", skipped)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 images, got %+v", targets)
+	}
+	if targets[0].Image != "ghcr.io/acme/shop-web:1.4.2" || targets[0].Build != nil {
+		t.Errorf("unexpected web target: %+v", targets[0])
+	}
+	// ":-" also applies to empty variables
+	if targets[1].Image != "redis:7.2" || !reflect.DeepEqual(targets[1].Services, []string{"cache", "sessions"}) {
+		t.Errorf("expected cache and sessions to share one image, got %+v", targets[1])
+	}
+
+	targets, skipped = project.ImageTargets([]string{"db"}, true)
+	if len(skipped) != 0 || len(targets) != 4 {
+		t.Fatalf("expected 4 images and nothing skipped, got %+v / %v", targets, skipped)
+	}
+	if targets[0].Image != "depscanity-ci-web:local" || targets[0].Build == nil {
+		t.Errorf("expected web to be built, got %+v", targets[0])
+	}
+	if targets[3].Image != "postgres:16" {
+		t.Errorf("expected db profile to be active, got %+v", targets[3])
+	}
+
+	targets, _ = project.ImageTargets([]string{"*"}, false)
+	if len(targets) != 4 {
+		t.Errorf("expected every profile with *, got %d images", len(targets))
+	}
+}
+
+func TestImageTargets_SanitizesServiceName(t *testing.T) {
+	project := &Project{Name: "ci", Services: []Service{{Name: "Web.API", Build: &trivy.BuildSpec{Context: "."}}}}
+
+	targets, _ := project.ImageTargets(nil, true)
+	if len(targets) != 1 || targets[0].Image != "depscanity-ci-webapi:local" {
+		t.Errorf("expected lower-cased, sanitized service tag, got %+v", targets)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	env := func(name string) (string, bool) {
+		switch name {
+		case "SET":
+			return "value", true
+		case "EMPTY":
+			return "", true
+		}
+		return "", false
+	}
+
+	cases := map[string]string{
+		"${SET}":            "value",
+		"$SET/x":            "value/x",
+		"${UNSET:-def}":     "def",
+		"${EMPTY:-def}":     "def",
+		"${EMPTY-def}":      "",
+		"${UNSET-def}":      "def",
+		"${SET:+alt}":       "alt",
+		"${UNSET:+alt}":     "",
+		"${UNSET:?missing}": "",
+		"$$SET":             "$SET",
+		"no vars":           "no vars",
+	}
+	for in, want := range cases {
+		if got := interpolate(in, env); got != want {
+			t.Errorf("interpolate(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
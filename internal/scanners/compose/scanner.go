@@ -0,0 +1,114 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+	"depscanity/internal/scanners/trivy"
+)
+
+// Scanner scans the images referenced by docker compose services with trivy image.
+type Scanner struct {
+	opts scanners.Options
+	// builds marks the targets whose image is built (--compose-build), set by Targets.
+	builds map[string]bool
+}
+
+// New returns the compose scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "compose" }
+
+// LockGroup leaves targets that build their image unlocked: trivy.ScanBuiltImage holds the
+// group only around the trivy scan.
+func (s *Scanner) LockGroup(target string) string {
+	if s.builds[target] {
+		return ""
+	}
+	return trivy.LockGroup
+}
+
+// Targets returns one "<compose file>#<service>" target per distinct image of the enabled services.
+// A compose file that cannot be parsed is returned as is so that Scan reports the error.
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	if s.opts.NoContainer {
+		return nil
+	}
+
+	active := ActiveProfiles(s.opts.ComposeProfiles)
+	s.builds = make(map[string]bool)
+	var targets []string
+	for _, path := range det.Docker {
+		if !IsComposeFile(path) {
+			continue
+		}
+		project, err := LoadProject(path)
+		if err != nil {
+			targets = append(targets, path)
+			continue
+		}
+		images, skipped := project.ImageTargets(active, s.opts.ComposeBuild)
+		for _, svc := range skipped {
+			fmt.Printf("  [Compose] Skipping service %s in %s: no image (build-only, use --compose-build)\n", svc, path)
+		}
+		for _, img := range images {
+			target := path + "#" + img.Services[0]
+			s.builds[target] = img.Build != nil
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+func (s *Scanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	path, service, _ := strings.Cut(target, "#")
+	project, err := LoadProject(path)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "compose",
+			Location: path,
+			Message:  fmt.Sprintf("failed to parse compose file: %v", err),
+		}}
+	}
+
+	images, _ := project.ImageTargets(ActiveProfiles(s.opts.ComposeProfiles), s.opts.ComposeBuild)
+	for _, img := range images {
+		if img.Services[0] == service {
+			return ScanImage(ctx, path, img, s.opts)
+		}
+	}
+	return nil, []report.ScannerError{{
+		Source:   "compose",
+		Location: path,
+		Message:  fmt.Sprintf("service %s not found", service),
+	}}
+}
+
+// ScanImage builds the image when needed, scans it with trivy and attributes the
+// findings to the compose services using it.
+func ScanImage(ctx context.Context, composeFile string, img ImageTarget, opts scanners.Options) ([]model.Finding, []report.ScannerError) {
+	var findings []model.Finding
+	var scannerErrors []report.ScannerError
+	if img.Build != nil {
+		findings, scannerErrors = trivy.ScanBuiltImage(ctx, *img.Build, img.Image, opts)
+	} else {
+		findings, scannerErrors = trivy.ScanTrivy(ctx, img.Image, opts)
+	}
+	for i := range findings {
+		f := &findings[i]
+		f.Source = "compose"
+		if f.Metadata == nil {
+			f.Metadata = make(map[string]any)
+		}
+		f.Metadata["compose_file"] = composeFile
+		f.Metadata["compose_service"] = strings.Join(img.Services, ", ")
+	}
+	return findings, scannerErrors
+}
@@ -0,0 +1,429 @@
+package compose
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The compose files are read with a small YAML subset parser (block mappings and
+// sequences, flow collections, quoted and block scalars, anchors, aliases and
+// merge keys), which covers what compose files use in practice.
+
+type nodeKind int
+
+const (
+	scalarNode nodeKind = iota
+	mapNode
+	seqNode
+)
+
+// node is a parsed YAML value.
+type node struct {
+	kind  nodeKind
+	value string
+	// keys keeps mapping keys in document order.
+	keys   []string
+	fields map[string]*node
+	items  []*node
+}
+
+func newMap() *node {
+	return &node{kind: mapNode, fields: make(map[string]*node)}
+}
+
+func (n *node) set(key string, value *node) {
+	if _, exists := n.fields[key]; !exists {
+		n.keys = append(n.keys, key)
+	}
+	n.fields[key] = value
+}
+
+// get returns a mapping field, or nil when n is not a mapping or lacks the key.
+func (n *node) get(key string) *node {
+	if n == nil || n.kind != mapNode {
+		return nil
+	}
+	return n.fields[key]
+}
+
+// str returns the scalar value, or "" for anything else.
+func (n *node) str() string {
+	if n == nil || n.kind != scalarNode {
+		return ""
+	}
+	return n.value
+}
+
+// list returns the scalars of a sequence; a single scalar is a one-element list.
+func (n *node) list() []string {
+	if n == nil {
+		return nil
+	}
+	switch n.kind {
+	case scalarNode:
+		if n.value == "" {
+			return nil
+		}
+		return []string{n.value}
+	case seqNode:
+		values := make([]string, 0, len(n.items))
+		for _, item := range n.items {
+			values = append(values, item.str())
+		}
+		return values
+	}
+	return nil
+}
+
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	lines   []yamlLine
+	pos     int
+	anchors map[string]*node
+}
+
+// parseYAML parses a single YAML document.
+func parseYAML(data []byte) (*node, error) {
+	p := &yamlParser{anchors: make(map[string]*node)}
+	for i, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimRight(raw, "\r")
+		text := strings.TrimSpace(stripComment(raw))
+		if text == "" || text == "---" || text == "..." {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		p.lines = append(p.lines, yamlLine{num: i + 1, indent: indent, text: text})
+	}
+	if len(p.lines) == 0 {
+		return newMap(), nil
+	}
+
+	root, err := p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", p.lines[p.pos].num)
+	}
+	return root, nil
+}
+
+func (p *yamlParser) parseBlock(indent int) (*node, error) {
+	if isSeqItem(p.lines[p.pos].text) {
+		return p.parseSeq(indent)
+	}
+	return p.parseMap(indent)
+}
+
+func (p *yamlParser) parseMap(indent int) (*node, error) {
+	m := newMap()
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+		}
+		key, value, ok := splitKey(l.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", l.num)
+		}
+		p.pos++
+
+		child, err := p.parseValue(value, indent, l.num)
+		if err != nil {
+			return nil, err
+		}
+		if key == "<<" {
+			mergeInto(m, child)
+			continue
+		}
+		m.set(key, child)
+	}
+	return m, nil
+}
+
+func (p *yamlParser) parseSeq(indent int) (*node, error) {
+	seq := &node{kind: seqNode}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent || (l.indent == indent && !isSeqItem(l.text)) {
+			break
+		}
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", l.num)
+		}
+
+		rest := strings.TrimSpace(strings.TrimPrefix(l.text, "-"))
+		if _, _, isEntry := splitKey(rest); isEntry || isSeqItem(rest) {
+			// "- key: value" opens a mapping whose keys line up with the first one
+			itemIndent := indent + len(l.text) - len(rest)
+			p.lines[p.pos] = yamlLine{num: l.num, indent: itemIndent, text: rest}
+			item, err := p.parseBlock(itemIndent)
+			if err != nil {
+				return nil, err
+			}
+			seq.items = append(seq.items, item)
+			continue
+		}
+
+		p.pos++
+		item, err := p.parseValue(rest, indent, l.num)
+		if err != nil {
+			return nil, err
+		}
+		seq.items = append(seq.items, item)
+	}
+	return seq, nil
+}
+
+// parseValue parses the value after "key:" or "-"; nested blocks are indented deeper than parent.
+// Compose files commonly put sequence items at the same indentation as their key.
+func (p *yamlParser) parseValue(value string, parent int, num int) (*node, error) {
+	var anchor string
+	if strings.HasPrefix(value, "&") {
+		anchor, value, _ = strings.Cut(value[1:], " ")
+		value = strings.TrimSpace(value)
+	}
+	if strings.HasPrefix(value, "!") {
+		// Tags (!!str, !reset, !override) do not change how values are read here
+		_, value, _ = strings.Cut(value, " ")
+		value = strings.TrimSpace(value)
+	}
+
+	var n *node
+	var err error
+	switch {
+	case strings.HasPrefix(value, "*"):
+		alias, ok := p.anchors[value[1:]]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown alias %s", num, value)
+		}
+		n = alias
+	case value == "|" || value == ">" || strings.HasPrefix(value, "|-") || strings.HasPrefix(value, "|+") ||
+		strings.HasPrefix(value, ">-") || strings.HasPrefix(value, ">+"):
+		n = p.parseBlockScalar(parent, value[0] == '>')
+	case value == "":
+		n = &node{kind: scalarNode}
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > parent || (next.indent == parent && isSeqItem(next.text)) {
+				n, err = p.parseBlock(next.indent)
+			}
+		}
+	default:
+		value = p.joinFlow(value)
+		n, err = parseFlow(value, num)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if anchor != "" {
+		p.anchors[anchor] = n
+	}
+	return n, nil
+}
+
+// parseBlockScalar collects the lines of a literal (|) or folded (>) scalar.
+func (p *yamlParser) parseBlockScalar(parent int, folded bool) *node {
+	var parts []string
+	for p.pos < len(p.lines) && p.lines[p.pos].indent > parent {
+		parts = append(parts, p.lines[p.pos].text)
+		p.pos++
+	}
+	sep := "\n"
+	if folded {
+		sep = " "
+	}
+	return &node{kind: scalarNode, value: strings.Join(parts, sep)}
+}
+
+// joinFlow appends continuation lines to a flow collection that spans several lines.
+func (p *yamlParser) joinFlow(value string) string {
+	if value[0] != '[' && value[0] != '{' {
+		return value
+	}
+	for flowDepth(value) > 0 && p.pos < len(p.lines) {
+		value += " " + p.lines[p.pos].text
+		p.pos++
+	}
+	return value
+}
+
+func parseFlow(value string, num int) (*node, error) {
+	if value == "" {
+		return &node{kind: scalarNode}, nil
+	}
+	switch value[0] {
+	case '[':
+		if !strings.HasSuffix(value, "]") {
+			return nil, fmt.Errorf("line %d: unterminated flow sequence", num)
+		}
+		seq := &node{kind: seqNode}
+		for _, part := range splitFlow(value[1 : len(value)-1]) {
+			item, err := parseFlow(part, num)
+			if err != nil {
+				return nil, err
+			}
+			seq.items = append(seq.items, item)
+		}
+		return seq, nil
+	case '{':
+		if !strings.HasSuffix(value, "}") {
+			return nil, fmt.Errorf("line %d: unterminated flow mapping", num)
+		}
+		m := newMap()
+		for _, part := range splitFlow(value[1 : len(value)-1]) {
+			key, val, ok := splitKey(part)
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected \"key: value\" in flow mapping", num)
+			}
+			item, err := parseFlow(val, num)
+			if err != nil {
+				return nil, err
+			}
+			m.set(key, item)
+		}
+		return m, nil
+	}
+	return &node{kind: scalarNode, value: unquote(value)}, nil
+}
+
+// splitFlow splits the inside of a flow collection on top-level commas.
+func splitFlow(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		parts = append(parts, last)
+	}
+	return parts
+}
+
+func flowDepth(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// mergeInto applies a "<<" merge key: fields already set on m win.
+func mergeInto(m *node, src *node) {
+	sources := []*node{src}
+	if src.kind == seqNode {
+		sources = src.items
+	}
+	for _, s := range sources {
+		if s.kind != mapNode {
+			continue
+		}
+		for _, key := range s.keys {
+			if _, exists := m.fields[key]; !exists {
+				m.set(key, s.fields[key])
+			}
+		}
+	}
+}
+
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits "key: value" (or "key:") into its parts.
+func splitKey(s string) (string, string, bool) {
+	if s == "" || s[0] == '[' || s[0] == '{' || isSeqItem(s) {
+		return "", "", false
+	}
+	if s[0] == '"' || s[0] == '\'' {
+		end := strings.IndexByte(s[1:], s[0])
+		if end < 0 {
+			return "", "", false
+		}
+		rest := s[end+2:]
+		if rest != ":" && !strings.HasPrefix(rest, ": ") {
+			return "", "", false
+		}
+		return s[1 : end+1], strings.TrimSpace(rest[1:]), true
+	}
+	if idx := strings.Index(s, ": "); idx >= 0 {
+		return strings.TrimSpace(s[:idx]), strings.TrimSpace(s[idx+2:]), true
+	}
+	if strings.HasSuffix(s, ":") {
+		return strings.TrimSpace(s[:len(s)-1]), "", true
+	}
+	return "", "", false
+}
+
+// stripComment removes a trailing "# comment" that is not inside a quoted scalar.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		startsToken := i == 0 || strings.ContainsRune(" \t:[{,-", rune(line[i-1]))
+		switch {
+		case (c == '"' || c == '\'') && startsToken:
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		if v, err := strconv.Unquote(s); err == nil {
+			return v
+		}
+		return s[1 : len(s)-1]
+	}
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
@@ -0,0 +1,181 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"depscanity/internal/model"
+)
+
+// Finding kinds stored in Metadata["kind"].
+const (
+	KindVulnerability = "vulnerability"
+	KindAbandoned     = "abandoned"
+)
+
+// Advisory is one entry of `composer audit --format=json`.
+type Advisory struct {
+	AdvisoryID       string `json:"advisoryId"`
+	PackageName      string `json:"packageName"`
+	AffectedVersions string `json:"affectedVersions"`
+	Title            string `json:"title"`
+	CVE              string `json:"cve"`
+	Link             string `json:"link"`
+	ReportedAt       string `json:"reportedAt"`
+	Severity         string `json:"severity"`
+	Sources          []struct {
+		Name     string `json:"name"`
+		RemoteID string `json:"remoteId"`
+	} `json:"sources"`
+}
+
+// auditOutput is the composer audit document.
+// PHP encodes empty maps as [], so both fields are decoded lazily.
+type auditOutput struct {
+	Advisories json.RawMessage `json:"advisories"`
+	Abandoned  json.RawMessage `json:"abandoned"`
+}
+
+type lockfile struct {
+	Packages    []lockedPackage `json:"packages"`
+	PackagesDev []lockedPackage `json:"packages-dev"`
+}
+
+type lockedPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ParseComposerLock returns the locked version of every package (including dev packages).
+func ParseComposerLock(data []byte) (map[string]string, error) {
+	var lock lockfile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]string)
+	for _, p := range append(lock.Packages, lock.PackagesDev...) {
+		versions[strings.ToLower(p.Name)] = strings.TrimPrefix(p.Version, "v")
+	}
+	return versions, nil
+}
+
+// ParseComposerAudit parses composer audit JSON into findings.
+// versions maps package names to the versions locked in composer.lock.
+func ParseComposerAudit(output string, versions map[string]string, lockPath string) ([]model.Finding, error) {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil, nil
+	}
+
+	var doc auditOutput
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal composer audit json: %w", err)
+	}
+
+	advisories := make(map[string][]Advisory)
+	if err := decodeMap(doc.Advisories, &advisories); err != nil {
+		return nil, fmt.Errorf("failed to decode advisories: %w", err)
+	}
+	abandoned := make(map[string]*string)
+	if err := decodeMap(doc.Abandoned, &abandoned); err != nil {
+		return nil, fmt.Errorf("failed to decode abandoned packages: %w", err)
+	}
+
+	var findings []model.Finding
+
+	for _, name := range sortedKeys(advisories) {
+		for _, a := range advisories[name] {
+			sev, _ := model.ParseSeverity(a.Severity)
+
+			f := model.Finding{
+				Source:           "composer",
+				Ecosystem:        "packagist",
+				Package:          name,
+				InstalledVersion: versions[strings.ToLower(name)],
+				VulnerabilityID:  advisoryID(a),
+				Severity:         sev,
+				Location:         lockPath,
+				Metadata: map[string]any{
+					"kind":              KindVulnerability,
+					"affected_versions": a.AffectedVersions,
+					"advisory_id":       a.AdvisoryID,
+				},
+			}
+			if a.Title != "" {
+				title := a.Title
+				f.Title = &title
+			}
+			if a.Link != "" {
+				url := a.Link
+				f.URL = &url
+			}
+			if a.ReportedAt != "" {
+				f.Metadata["reported_at"] = a.ReportedAt
+			}
+
+			findings = append(findings, f)
+		}
+	}
+
+	// Abandoned packages are package health findings: medium, like a deprecated package
+	for _, name := range sortedKeys(abandoned) {
+		title := fmt.Sprintf("%s is abandoned", name)
+		if replacement := abandoned[name]; replacement != nil && *replacement != "" {
+			title = fmt.Sprintf("%s is abandoned, use %s instead", name, *replacement)
+		}
+		f := model.Finding{
+			Source:           "composer",
+			Ecosystem:        "packagist",
+			Package:          name,
+			InstalledVersion: versions[strings.ToLower(name)],
+			VulnerabilityID:  "abandoned:" + name,
+			Severity:         model.SeverityMedium,
+			Title:            &title,
+			Location:         lockPath,
+			Class:            model.ClassAbandoned,
+			Metadata: map[string]any{
+				"kind": KindAbandoned,
+			},
+		}
+		if replacement := abandoned[name]; replacement != nil && *replacement != "" {
+			f.Metadata["replacement"] = *replacement
+		}
+		findings = append(findings, f)
+	}
+
+	return findings, nil
+}
+
+// advisoryID prefers the CVE, then the GHSA identifier, then the Packagist advisory ID.
+func advisoryID(a Advisory) string {
+	if a.CVE != "" {
+		return a.CVE
+	}
+	for _, s := range a.Sources {
+		if strings.HasPrefix(s.RemoteID, "GHSA-") {
+			return s.RemoteID
+		}
+	}
+	return a.AdvisoryID
+}
+
+// decodeMap decodes a JSON object into dst, treating [] and null as empty.
+func decodeMap(raw json.RawMessage, dst any) error {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" || trimmed == "[]" {
+		return nil
+	}
+	return json.Unmarshal(raw, dst)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
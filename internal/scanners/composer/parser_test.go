@@ -0,0 +1,66 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"depscanity/internal/model"
+)
+
+func TestParseComposerAudit(t *testing.T) {
+	lockData, err := os.ReadFile(filepath.Join("testdata", "composer.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err := ParseComposerLock(lockData)
+	if err != nil {
+		t.Fatalf("Lock parse failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join("testdata", "composer_audit_output.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParseComposerAudit(string(data), versions, "/repo/composer.lock")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings (2 advisories, 1 abandoned), got %d", len(findings))
+	}
+
+	// Advisories are sorted by package name
+	guzzle := findings[0]
+	if guzzle.Ecosystem != "packagist" || guzzle.Package != "guzzlehttp/guzzle" || guzzle.InstalledVersion != "7.4.0" {
+		t.Errorf("unexpected identity: %s %s %s", guzzle.Ecosystem, guzzle.Package, guzzle.InstalledVersion)
+	}
+	if guzzle.VulnerabilityID != "GHSA-q559-8m2m-g699" || guzzle.Severity != model.SeverityHigh {
+		t.Errorf("expected GHSA fallback with high severity, got %s %s", guzzle.VulnerabilityID, guzzle.Severity)
+	}
+
+	kernel := findings[1]
+	if kernel.VulnerabilityID != "CVE-2022-24894" || kernel.InstalledVersion != "5.4.19" {
+		t.Errorf("unexpected symfony finding: %s %s", kernel.VulnerabilityID, kernel.InstalledVersion)
+	}
+	if kernel.Metadata["kind"] != KindVulnerability {
+		t.Errorf("expected vulnerability kind, got %v", kernel.Metadata["kind"])
+	}
+
+	timer := findings[2]
+	if timer.Metadata["kind"] != KindAbandoned || timer.Severity != model.SeverityMedium || !timer.IsPackageHealth() {
+		t.Errorf("unexpected abandoned finding: %v %s %q", timer.Metadata["kind"], timer.Severity, timer.Class)
+	}
+}
+
+func TestParseComposerAuditEmpty(t *testing.T) {
+	findings, err := ParseComposerAudit(`{"advisories": [], "abandoned": []}`, nil, "/repo/composer.lock")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected no findings, got %d", len(findings))
+	}
+}
@@ -0,0 +1,114 @@
+package composer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+)
+
+// MaxLockfiles caps how many composer.lock files are audited per run.
+const MaxLockfiles = 10
+
+// Scanner audits composer.lock files with composer audit.
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the composer scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "composer" }
+
+func (s *Scanner) MaxTargets() int { return MaxLockfiles }
+
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	return det.Php
+}
+
+func (s *Scanner) Scan(ctx context.Context, lockPath string) ([]model.Finding, []report.ScannerError) {
+	findings, err := ScanComposer(ctx, lockPath, s.opts)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "composer",
+			Location: lockPath,
+			Message:  err.Error(),
+		}}
+	}
+	return findings, nil
+}
+
+// ScanComposer executes composer audit against the lockfile (no vendor/ needed) and parses the results.
+func ScanComposer(ctx context.Context, lockPath string, opts scanners.Options) ([]model.Finding, error) {
+	// 1. Setup paths
+	workDir := filepath.Dir(lockPath)
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
+	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create raw output dir: %w", err)
+	}
+
+	// Installed versions are not part of the audit output
+	lockData, err := os.ReadFile(lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read composer.lock: %w", err)
+	}
+	versions, err := ParseComposerLock(lockData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse composer.lock: %w", err)
+	}
+
+	// 2. Check composer existence
+	if _, err := exec.LookPath("composer"); err != nil {
+		return nil, fmt.Errorf("composer executable not found in PATH")
+	}
+
+	// 3. Execution
+	args := []string{"audit", "--format=json", "--locked", "--no-interaction"}
+	res, err := opts.RunPhase(ctx, "composer", scanners.PhaseAudit, lockPath, "composer", args, workDir)
+	// composer audit exits non-zero when advisories or abandoned packages are found
+	if res.ExitCode == 124 {
+		return nil, fmt.Errorf("composer audit timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit))
+	}
+	if res.ExitCode == 127 {
+		return nil, fmt.Errorf("composer audit failed execution (code %d): %v", res.ExitCode, err)
+	}
+
+	// 4. Save raw output
+	sanitizedName := sanitizePath(workDir)
+	rawFile := filepath.Join(rawOutDir, fmt.Sprintf("composer-%s.json", sanitizedName))
+	if err := os.WriteFile(rawFile, []byte(res.Stdout), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write raw output: %w", err)
+	}
+
+	if res.ExitCode != 0 && strings.TrimSpace(res.Stdout) == "" {
+		return nil, fmt.Errorf("composer audit failed (code %d): %v\nStderr: %s", res.ExitCode, err, res.Stderr)
+	}
+
+	// 5. Parse
+	findings, err := ParseComposerAudit(res.Stdout, versions, lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	return findings, nil
+}
+
+func sanitizePath(path string) string {
+	s := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, path)
+	return strings.Trim(s, "_")
+}
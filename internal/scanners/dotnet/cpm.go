@@ -0,0 +1,175 @@
+package dotnet
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"depscanity/internal/model"
+)
+
+// PackagesPropsFile is the MSBuild file that enables NuGet Central Package Management.
+const PackagesPropsFile = "Directory.Packages.props"
+
+// CentralPin is a version pinned centrally with PackageVersion or GlobalPackageReference.
+type CentralPin struct {
+	Package string
+	Version string
+	File    string
+	Global  bool
+}
+
+var propertyRefRegex = regexp.MustCompile(`\$\(([A-Za-z0-9_.\-]+)\)`)
+
+// ParsePackagesProps reads the central pins of a Directory.Packages.props file, keyed by
+// lower-case package ID. $(Property) references to properties defined in the same file are expanded.
+func ParsePackagesProps(r io.Reader, file string) (map[string]CentralPin, error) {
+	properties := make(map[string]string)
+	pins := make(map[string]CentralPin)
+
+	decoder := xml.NewDecoder(r)
+	inPropertyGroup := false
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "PropertyGroup":
+				inPropertyGroup = true
+			case "PackageVersion", "GlobalPackageReference":
+				var name, version string
+				for _, attr := range t.Attr {
+					switch attr.Name.Local {
+					case "Include", "Update":
+						name = attr.Value
+					case "Version":
+						version = attr.Value
+					}
+				}
+				if name == "" || version == "" {
+					continue
+				}
+				pins[strings.ToLower(name)] = CentralPin{
+					Package: name,
+					Version: version,
+					File:    file,
+					Global:  t.Name.Local == "GlobalPackageReference",
+				}
+			default:
+				if inPropertyGroup {
+					var value string
+					if err := decoder.DecodeElement(&value, &t); err == nil {
+						properties[t.Name.Local] = strings.TrimSpace(value)
+					}
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "PropertyGroup" {
+				inPropertyGroup = false
+			}
+		}
+	}
+
+	for key, pin := range pins {
+		pin.Version = propertyRefRegex.ReplaceAllStringFunc(pin.Version, func(ref string) string {
+			if v, ok := properties[propertyRefRegex.FindStringSubmatch(ref)[1]]; ok {
+				return v
+			}
+			return ref
+		})
+		pins[key] = pin
+	}
+
+	return pins, nil
+}
+
+// findPackagesProps returns the nearest Directory.Packages.props at or above the
+// project directory, as MSBuild resolves it, or "" when the project does not use CPM.
+func findPackagesProps(projectPath string) string {
+	dir := projectPath
+	if info, err := os.Stat(projectPath); err != nil || !info.IsDir() {
+		dir = filepath.Dir(projectPath)
+	}
+	dir, _ = filepath.Abs(dir)
+
+	for {
+		candidate := filepath.Join(dir, PackagesPropsFile)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// annotateCentralPins adds the centrally pinned version (and the props file declaring it)
+// to findings of projects that use Central Package Management.
+func annotateCentralPins(findings []model.Finding, target string, inventories []*Inventory) {
+	propsByProject := make(map[string]string)
+	pinsByProps := make(map[string]map[string]CentralPin)
+
+	for i := range findings {
+		f := &findings[i]
+		project := resolveProject(f, target, inventories)
+
+		props, ok := propsByProject[project]
+		if !ok {
+			props = findPackagesProps(project)
+			propsByProject[project] = props
+		}
+		if props == "" {
+			continue
+		}
+
+		pins, ok := pinsByProps[props]
+		if !ok {
+			if file, err := os.Open(props); err == nil {
+				pins, err = ParsePackagesProps(file, props)
+				file.Close()
+				if err != nil {
+					fmt.Printf("  [Dotnet] %v\n", err)
+				}
+			}
+			pinsByProps[props] = pins
+		}
+
+		if pin, ok := pins[strings.ToLower(f.Package)]; ok {
+			f.Metadata["cpm_version"] = pin.Version
+			f.Metadata["cpm_file"] = pin.File
+			if pin.Global {
+				f.Metadata["cpm_global"] = true
+			}
+		}
+	}
+}
+
+// resolveProject returns the project file a finding belongs to, falling back to the scan target.
+func resolveProject(f *model.Finding, target string, inventories []*Inventory) string {
+	project, _ := f.Metadata["project"].(string)
+	if project == "" {
+		return target
+	}
+	if _, err := os.Stat(project); err == nil {
+		return project
+	}
+	for _, inv := range inventories {
+		if sameProject(inv.Project, project) {
+			return inv.Project
+		}
+	}
+	return target
+}
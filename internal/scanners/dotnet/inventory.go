@@ -0,0 +1,341 @@
+package dotnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+)
+
+// Inventory is the resolved NuGet graph of one project, read from obj/project.assets.json
+// (written by restore) or packages.lock.json (lock mode) without running dotnet.
+type Inventory struct {
+	Project    string               `json:"project"`
+	Source     string               `json:"source"`
+	Frameworks []FrameworkInventory `json:"frameworks"`
+}
+
+// FrameworkInventory lists the packages resolved for one target framework.
+type FrameworkInventory struct {
+	Framework string             `json:"framework"`
+	Packages  []InventoryPackage `json:"packages"`
+}
+
+// InventoryPackage is a resolved package and how it entered the graph.
+type InventoryPackage struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Requested string `json:"requested,omitempty"`
+	Direct    bool   `json:"direct"`
+	// Chain runs from the top-level reference to this package (inclusive).
+	Chain []string `json:"chain"`
+}
+
+// IntroducedVia renders the dependency chain as "A → B → C".
+func (p InventoryPackage) IntroducedVia() string {
+	return strings.Join(p.Chain, " → ")
+}
+
+// graphNode is a package or project reference inside one framework's graph.
+type graphNode struct {
+	name      string
+	version   string
+	requested string
+	project   bool
+	deps      []string
+}
+
+// LoadInventory reads the NuGet graph of a project file (or project directory).
+// obj/project.assets.json is preferred; packages.lock.json is used when no restore output exists.
+func LoadInventory(projectPath string) (*Inventory, error) {
+	projectDir := projectPath
+	if info, err := os.Stat(projectPath); err != nil || !info.IsDir() {
+		projectDir = filepath.Dir(projectPath)
+	}
+
+	assetsPath := filepath.Join(projectDir, "obj", "project.assets.json")
+	if data, err := os.ReadFile(assetsPath); err == nil {
+		frameworks, err := ParseAssetsFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", assetsPath, err)
+		}
+		return &Inventory{Project: projectPath, Source: assetsPath, Frameworks: frameworks}, nil
+	}
+
+	lockPath := filepath.Join(projectDir, "packages.lock.json")
+	if data, err := os.ReadFile(lockPath); err == nil {
+		frameworks, err := ParsePackagesLock(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", lockPath, err)
+		}
+		return &Inventory{Project: projectPath, Source: lockPath, Frameworks: frameworks}, nil
+	}
+
+	return nil, fmt.Errorf("no obj/project.assets.json or packages.lock.json for %s (run dotnet restore)", projectPath)
+}
+
+// ParseAssetsFile builds the per-framework inventory from project.assets.json.
+// Top-level references come from projectFileDependencyGroups ("Name >= 1.2.3"), which only
+// lists packages, and from the project references (type "project" in the targets);
+// runtime-specific targets ("net8.0/win-x64") are skipped.
+func ParseAssetsFile(data []byte) ([]FrameworkInventory, error) {
+	var assets struct {
+		Targets map[string]map[string]struct {
+			Type         string            `json:"type"`
+			Dependencies map[string]string `json:"dependencies"`
+		} `json:"targets"`
+		ProjectFileDependencyGroups map[string][]string `json:"projectFileDependencyGroups"`
+	}
+	if err := json.Unmarshal(data, &assets); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal project.assets.json: %w", err)
+	}
+
+	var result []FrameworkInventory
+	for framework, libs := range assets.Targets {
+		if strings.Contains(framework, "/") {
+			continue
+		}
+
+		nodes := make(map[string]*graphNode)
+		for key, lib := range libs {
+			name, version, ok := strings.Cut(key, "/")
+			if !ok {
+				continue
+			}
+			nodes[strings.ToLower(name)] = &graphNode{
+				name:    name,
+				version: version,
+				project: lib.Type == "project",
+				deps:    sortedKeys(lib.Dependencies),
+			}
+		}
+
+		var roots []string
+		for _, spec := range assets.ProjectFileDependencyGroups[framework] {
+			name, requested, _ := strings.Cut(spec, " ")
+			if node, ok := nodes[strings.ToLower(name)]; ok {
+				node.requested = strings.TrimSpace(requested)
+				roots = append(roots, name)
+			}
+		}
+		var projects []string
+		for _, node := range nodes {
+			if node.project {
+				projects = append(projects, node.name)
+			}
+		}
+		sort.Strings(projects)
+		roots = append(roots, projects...)
+
+		result = append(result, FrameworkInventory{Framework: framework, Packages: walkGraph(nodes, roots)})
+	}
+
+	sortFrameworks(result)
+	return result, nil
+}
+
+// ParsePackagesLock builds the per-framework inventory from packages.lock.json.
+func ParsePackagesLock(data []byte) ([]FrameworkInventory, error) {
+	var lock struct {
+		Dependencies map[string]map[string]struct {
+			Type         string            `json:"type"`
+			Requested    string            `json:"requested"`
+			Resolved     string            `json:"resolved"`
+			Dependencies map[string]string `json:"dependencies"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal packages.lock.json: %w", err)
+	}
+
+	var result []FrameworkInventory
+	for framework, deps := range lock.Dependencies {
+		if strings.Contains(framework, "/") {
+			continue
+		}
+
+		nodes := make(map[string]*graphNode)
+		var roots []string
+		for name, dep := range deps {
+			nodes[strings.ToLower(name)] = &graphNode{
+				name:      name,
+				version:   dep.Resolved,
+				requested: dep.Requested,
+				project:   strings.EqualFold(dep.Type, "Project"),
+				deps:      sortedKeys(dep.Dependencies),
+			}
+			if strings.EqualFold(dep.Type, "Direct") || strings.EqualFold(dep.Type, "Project") {
+				roots = append(roots, name)
+			}
+		}
+		sort.Strings(roots)
+
+		result = append(result, FrameworkInventory{Framework: framework, Packages: walkGraph(nodes, roots)})
+	}
+
+	sortFrameworks(result)
+	return result, nil
+}
+
+// walkGraph runs a breadth-first search from the top-level references so every package
+// gets its shortest chain. Project references are traversed but not listed.
+func walkGraph(nodes map[string]*graphNode, roots []string) []InventoryPackage {
+	chains := make(map[string][]string)
+	queue := make([]string, 0, len(roots))
+	for _, root := range roots {
+		key := strings.ToLower(root)
+		if _, seen := chains[key]; seen {
+			continue
+		}
+		chains[key] = []string{nodes[key].name}
+		queue = append(queue, key)
+	}
+
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, dep := range nodes[key].deps {
+			depKey := strings.ToLower(dep)
+			node, ok := nodes[depKey]
+			if !ok {
+				continue
+			}
+			if _, seen := chains[depKey]; seen {
+				continue
+			}
+			chain := append(append([]string{}, chains[key]...), node.name)
+			chains[depKey] = chain
+			queue = append(queue, depKey)
+		}
+	}
+
+	var packages []InventoryPackage
+	for key, node := range nodes {
+		if node.project {
+			continue
+		}
+		chain, reachable := chains[key]
+		if !reachable {
+			chain = []string{node.name}
+		}
+		packages = append(packages, InventoryPackage{
+			Name:      node.name,
+			Version:   node.version,
+			Requested: node.requested,
+			Direct:    reachable && len(chain) == 1,
+			Chain:     chain,
+		})
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return strings.ToLower(packages[i].Name) < strings.ToLower(packages[j].Name)
+	})
+	return packages
+}
+
+// Lookup finds a package in the given framework. When the framework is unknown or
+// spelled differently (".NETCoreApp,Version=v8.0" vs "net8.0") and the project has a
+// single framework, that framework is used.
+func (inv *Inventory) Lookup(framework, name string) (InventoryPackage, bool) {
+	for _, fw := range inv.Frameworks {
+		if framework != "" && !strings.EqualFold(fw.Framework, framework) && len(inv.Frameworks) > 1 {
+			continue
+		}
+		for _, p := range fw.Packages {
+			if strings.EqualFold(p.Name, name) {
+				return p, true
+			}
+		}
+	}
+	return InventoryPackage{}, false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortFrameworks(frameworks []FrameworkInventory) {
+	sort.Slice(frameworks, func(i, j int) bool {
+		return frameworks[i].Framework < frameworks[j].Framework
+	})
+}
+
+// projectsForTarget returns the project files covered by a scan target:
+// the projects of a solution, the project itself, or the directory for the root fallback.
+func projectsForTarget(target string) []string {
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		return []string{target}
+	}
+	if !detect.IsDotnetSolution(target) {
+		return []string{target}
+	}
+
+	included, err := getProjectsInSolutions([]string{target})
+	if err != nil {
+		return nil
+	}
+	projects := make([]string, 0, len(included))
+	for p := range included {
+		projects = append(projects, p)
+	}
+	sort.Strings(projects)
+	return projects
+}
+
+// loadInventories loads the inventory of every project in the target.
+// Projects that were never restored are skipped.
+func loadInventories(target string) []*Inventory {
+	var inventories []*Inventory
+	for _, project := range projectsForTarget(target) {
+		inv, err := LoadInventory(project)
+		if err != nil {
+			fmt.Printf("  [Dotnet] No inventory for %s: %v\n", project, err)
+			continue
+		}
+		inventories = append(inventories, inv)
+	}
+	return inventories
+}
+
+// annotateChains adds the dependency chain to findings whose package is found
+// in the inventory of the finding's project.
+func annotateChains(findings []model.Finding, inventories []*Inventory) {
+	for i := range findings {
+		f := &findings[i]
+		project, _ := f.Metadata["project"].(string)
+		framework, _ := f.Metadata["framework"].(string)
+
+		for _, inv := range inventories {
+			if project != "" && len(inventories) > 1 && !sameProject(inv.Project, project) {
+				continue
+			}
+			if pkg, ok := inv.Lookup(framework, f.Package); ok {
+				f.Metadata["dependency_chain"] = pkg.Chain
+				// A package not reachable from any top-level reference has no chain to show
+				if !pkg.Direct && len(pkg.Chain) >= 2 {
+					f.Metadata["introduced_via"] = pkg.IntroducedVia()
+				}
+				break
+			}
+		}
+	}
+}
+
+// sameProject matches a project path against the project reference of a finding,
+// which is a full path (JSON output) or just the project name (text output).
+func sameProject(projectPath, ref string) bool {
+	if filepath.Clean(projectPath) == filepath.Clean(ref) {
+		return true
+	}
+	name := strings.TrimSuffix(filepath.Base(projectPath), filepath.Ext(projectPath))
+	return strings.EqualFold(name, ref)
+}
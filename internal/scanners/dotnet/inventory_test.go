@@ -0,0 +1,114 @@
+package dotnet
+
+import (
+	"path/filepath"
+	"testing"
+
+	"depscanity/internal/model"
+)
+
+func TestLoadInventory_Assets(t *testing.T) {
+	inv, err := LoadInventory(filepath.Join("testdata", "WebApp", "WebApp.csproj"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(inv.Frameworks) != 1 || inv.Frameworks[0].Framework != "net8.0" {
+		t.Fatalf("expected only the net8.0 framework, got %+v", inv.Frameworks)
+	}
+	if len(inv.Frameworks[0].Packages) != 4 {
+		t.Fatalf("expected 4 packages (project references excluded), got %d", len(inv.Frameworks[0].Packages))
+	}
+
+	jwt, ok := inv.Lookup("net8.0", "System.IdentityModel.Tokens.Jwt")
+	if !ok {
+		t.Fatal("expected System.IdentityModel.Tokens.Jwt in inventory")
+	}
+	if jwt.Direct || jwt.IntroducedVia() != "Microsoft.AspNetCore.Authentication.JwtBearer → Microsoft.IdentityModel.Protocols.OpenIdConnect → System.IdentityModel.Tokens.Jwt" {
+		t.Errorf("unexpected chain: %v", jwt.Chain)
+	}
+
+	bearer, _ := inv.Lookup("net8.0", "Microsoft.AspNetCore.Authentication.JwtBearer")
+	if !bearer.Direct || bearer.Requested != ">= 8.0.0" {
+		t.Errorf("expected direct reference with requested version, got %+v", bearer)
+	}
+
+	// Packages brought in by a project reference are chained through the project
+	newtonsoft, _ := inv.Lookup("net8.0", "Newtonsoft.Json")
+	if newtonsoft.IntroducedVia() != "WebApp.Core → Newtonsoft.Json" {
+		t.Errorf("unexpected chain: %v", newtonsoft.Chain)
+	}
+}
+
+func TestLoadInventory_PackagesLock(t *testing.T) {
+	inv, err := LoadInventory(filepath.Join("testdata", "Worker", "Worker.csproj"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if filepath.Base(inv.Source) != "packages.lock.json" {
+		t.Errorf("expected packages.lock.json source, got %s", inv.Source)
+	}
+
+	serilog, ok := inv.Lookup("", "serilog")
+	if !ok || serilog.Version != "2.10.0" || serilog.IntroducedVia() != "Serilog.Sinks.File → Serilog" {
+		t.Errorf("unexpected Serilog entry: %+v", serilog)
+	}
+}
+
+func TestAnnotateChains(t *testing.T) {
+	inv, err := LoadInventory(filepath.Join("testdata", "WebApp", "WebApp.csproj"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings := []model.Finding{
+		{Package: "System.IdentityModel.Tokens.Jwt", Metadata: map[string]any{"project": "WebApp", "framework": "net8.0"}},
+		{Package: "Microsoft.AspNetCore.Authentication.JwtBearer", Metadata: map[string]any{}},
+	}
+	annotateChains(findings, []*Inventory{inv})
+
+	if findings[0].Metadata["introduced_via"] == nil {
+		t.Errorf("expected introduced_via on transitive finding, got %v", findings[0].Metadata)
+	}
+	if _, ok := findings[1].Metadata["introduced_via"]; ok {
+		t.Error("direct references should not get introduced_via")
+	}
+	if chain, _ := findings[1].Metadata["dependency_chain"].([]string); len(chain) != 1 {
+		t.Errorf("expected single-element chain for direct reference, got %v", chain)
+	}
+
+	// A package no top-level reference leads to has no chain to introduce it through
+	orphan := &Inventory{Frameworks: []FrameworkInventory{{
+		Framework: "net8.0",
+		Packages:  []InventoryPackage{{Name: "Orphan", Version: "1.0.0", Chain: []string{"Orphan"}}},
+	}}}
+	findings = []model.Finding{{Package: "Orphan", Metadata: map[string]any{}}}
+	annotateChains(findings, []*Inventory{orphan})
+	if _, ok := findings[0].Metadata["introduced_via"]; ok {
+		t.Errorf("unreachable packages should not get introduced_via, got %v", findings[0].Metadata)
+	}
+}
+
+func TestAnnotateCentralPins(t *testing.T) {
+	project := filepath.Join("testdata", "Cpm", "src", "Api", "Api.csproj")
+
+	findings := []model.Finding{
+		{Package: "System.IdentityModel.Tokens.Jwt", Metadata: map[string]any{}},
+		{Package: "nerdbank.gitversioning", Metadata: map[string]any{}},
+		{Package: "Serilog", Metadata: map[string]any{}},
+	}
+	annotateCentralPins(findings, project, nil)
+
+	if findings[0].Metadata["cpm_version"] != "7.0.3" {
+		t.Errorf("expected property-expanded pin 7.0.3, got %v", findings[0].Metadata["cpm_version"])
+	}
+	if file, _ := findings[0].Metadata["cpm_file"].(string); filepath.Base(file) != PackagesPropsFile {
+		t.Errorf("expected pin file %s, got %v", PackagesPropsFile, findings[0].Metadata["cpm_file"])
+	}
+	if findings[1].Metadata["cpm_global"] != true {
+		t.Errorf("expected global package reference, got %v", findings[1].Metadata)
+	}
+	if _, ok := findings[2].Metadata["cpm_version"]; ok {
+		t.Error("unpinned packages should not get cpm_version")
+	}
+}
@@ -11,8 +11,12 @@ import (
 var (
 	severityKeywords = []string{"Critical", "High", "Moderate", "Medium", "Low"}
 	urlRegex         = regexp.MustCompile(`https?://[^\s>]+`)
+	projectRegex     = regexp.MustCompile("^Project [`'](.+?)[`'] has the following")
+	frameworkRegex   = regexp.MustCompile(`^\s*\[([^\]]+)\]:`)
 )
 
+// ParseDotnetOutput scrapes the console table of dotnet list package --vulnerable.
+// It is the fallback for SDKs that predate --format json (see ParseDotnetJSON).
 func ParseDotnetOutput(output string, sourcePath string) ([]model.Finding, error) {
 	var findings []model.Finding
 	scanner := bufio.NewScanner(strings.NewReader(output))
@@ -21,15 +25,31 @@ func ParseDotnetOutput(output string, sourcePath string) ([]model.Finding, error
 	sevRegex := regexp.MustCompile(`(?i)\b(Critical|High|Moderate|Medium|Low)\b`)
 
 	var lastPkg, lastVer string
+	var project, framework string
+	topLevel := true
 
 	for scanner.Scan() {
 		line := scanner.Text()
 		lineLower := strings.ToLower(line)
 
-		// 1. Skip known header/noise lines
+		// 1. Track project/framework/section headers and skip known noise lines
+		if m := projectRegex.FindStringSubmatch(line); m != nil {
+			project = m[1]
+			continue
+		}
+		if m := frameworkRegex.FindStringSubmatch(line); m != nil {
+			framework = m[1]
+			continue
+		}
+		if strings.Contains(lineLower, "top-level package") {
+			topLevel = true
+			continue
+		}
+		if strings.Contains(lineLower, "transitive package") {
+			topLevel = false
+			continue
+		}
 		if strings.Contains(lineLower, "the following") ||
-			strings.Contains(lineLower, "top-level package") ||
-			strings.Contains(lineLower, "transitive package") ||
 			strings.Contains(lineLower, "project `") {
 			continue
 		}
@@ -110,10 +130,7 @@ func ParseDotnetOutput(output string, sourcePath string) ([]model.Finding, error
 
 		// URL detection
 		url := urlRegex.FindString(line)
-		vulnID := "dotnet-advisory"
-		if url != "" {
-			vulnID = url
-		}
+		vulnID := advisoryID(url)
 
 		f := model.Finding{
 			Source:           "dotnet",
@@ -124,9 +141,16 @@ func ParseDotnetOutput(output string, sourcePath string) ([]model.Finding, error
 			Severity:         sev,
 			Location:         sourcePath,
 			Metadata: map[string]any{
-				"raw_line": line,
+				"raw_line":  line,
+				"top_level": topLevel,
 			},
 		}
+		if project != "" {
+			f.Metadata["project"] = project
+		}
+		if framework != "" {
+			f.Metadata["framework"] = framework
+		}
 		if url != "" {
 			f.URL = &url
 		}
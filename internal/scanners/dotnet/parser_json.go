@@ -0,0 +1,188 @@
+package dotnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"depscanity/internal/model"
+)
+
+// ListPackageReport is the document written by
+// `dotnet list package --vulnerable|--deprecated|--outdated --format json --output-version 1` (SDK 7.0.200+).
+type ListPackageReport struct {
+	Version    int       `json:"version"`
+	Parameters string    `json:"parameters"`
+	Problems   []Problem `json:"problems"`
+	Projects   []struct {
+		Path       string `json:"path"`
+		Frameworks []struct {
+			Framework          string          `json:"framework"`
+			TopLevelPackages   []ListedPackage `json:"topLevelPackages"`
+			TransitivePackages []ListedPackage `json:"transitivePackages"`
+		} `json:"frameworks"`
+	} `json:"projects"`
+}
+
+// Problem is a warning or error reported by the SDK (e.g. a project that was not restored).
+type Problem struct {
+	Project string `json:"project"`
+	Level   string `json:"level"`
+	Text    string `json:"text"`
+}
+
+// ListedPackage is a package entry with its advisories (--vulnerable),
+// deprecation data (--deprecated) or latest version (--outdated).
+type ListedPackage struct {
+	ID               string `json:"id"`
+	RequestedVersion string `json:"requestedVersion"`
+	ResolvedVersion  string `json:"resolvedVersion"`
+	Vulnerabilities  []struct {
+		Severity    string `json:"severity"`
+		AdvisoryURL string `json:"advisoryurl"`
+	} `json:"vulnerabilities"`
+	DeprecationReasons []string `json:"deprecationReasons"`
+	AlternativePackage *struct {
+		ID           string `json:"id"`
+		VersionRange string `json:"versionRange"`
+	} `json:"alternativePackage"`
+	LatestVersion string `json:"latestVersion"`
+}
+
+var ghsaRegex = regexp.MustCompile(`GHSA(-[0-9a-z]{4}){3}`)
+
+// ParseDotnetJSON parses the JSON output of dotnet list package --vulnerable.
+// SDK problems are returned alongside the findings so the caller can surface them.
+func ParseDotnetJSON(output string, sourcePath string) ([]model.Finding, []Problem, error) {
+	var rep ListPackageReport
+	if err := json.Unmarshal([]byte(output), &rep); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal dotnet json: %w", err)
+	}
+
+	var findings []model.Finding
+	for _, project := range rep.Projects {
+		for _, fw := range project.Frameworks {
+			for _, group := range []struct {
+				topLevel bool
+				packages []ListedPackage
+			}{{true, fw.TopLevelPackages}, {false, fw.TransitivePackages}} {
+				for _, pkg := range group.packages {
+					for _, v := range pkg.Vulnerabilities {
+						sev, _ := model.ParseSeverity(v.Severity)
+
+						f := model.Finding{
+							Source:           "dotnet",
+							Ecosystem:        "nuget",
+							Package:          pkg.ID,
+							InstalledVersion: pkg.ResolvedVersion,
+							VulnerabilityID:  advisoryID(v.AdvisoryURL),
+							Severity:         sev,
+							Location:         sourcePath,
+							Metadata: map[string]any{
+								"project":          project.Path,
+								"framework":        fw.Framework,
+								"top_level":        group.topLevel,
+								"resolved_version": pkg.ResolvedVersion,
+							},
+						}
+						if pkg.RequestedVersion != "" {
+							f.Metadata["requested_version"] = pkg.RequestedVersion
+						}
+						if v.AdvisoryURL != "" {
+							url := v.AdvisoryURL
+							f.URL = &url
+						}
+
+						findings = append(findings, f)
+					}
+				}
+			}
+		}
+	}
+
+	return findings, rep.Problems, nil
+}
+
+// advisoryID returns the GHSA identifier embedded in an advisory URL,
+// falling back to the URL itself (or a placeholder when there is none).
+func advisoryID(url string) string {
+	if id := ghsaRegex.FindString(url); id != "" {
+		return id
+	}
+	if url != "" {
+		return url
+	}
+	return "dotnet-advisory"
+}
+
+// ParseDotnetHealthJSON parses `dotnet list package --deprecated` (class deprecated) or
+// `--outdated` (class outdated) JSON output into package health findings.
+// Deprecations flagged CriticalBugs are high, other deprecations medium, outdated packages low.
+func ParseDotnetHealthJSON(output string, sourcePath string, class model.FindingClass) ([]model.Finding, []Problem, error) {
+	var rep ListPackageReport
+	if err := json.Unmarshal([]byte(output), &rep); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal dotnet json: %w", err)
+	}
+
+	var findings []model.Finding
+	for _, project := range rep.Projects {
+		for _, fw := range project.Frameworks {
+			for _, group := range []struct {
+				topLevel bool
+				packages []ListedPackage
+			}{{true, fw.TopLevelPackages}, {false, fw.TransitivePackages}} {
+				for _, pkg := range group.packages {
+					f := model.Finding{
+						Source:           "dotnet",
+						Ecosystem:        "nuget",
+						Package:          pkg.ID,
+						InstalledVersion: pkg.ResolvedVersion,
+						VulnerabilityID:  fmt.Sprintf("%s:%s", class, pkg.ID),
+						Location:         sourcePath,
+						Class:            class,
+						Metadata: map[string]any{
+							"project":          project.Path,
+							"framework":        fw.Framework,
+							"top_level":        group.topLevel,
+							"resolved_version": pkg.ResolvedVersion,
+						},
+					}
+					if pkg.RequestedVersion != "" {
+						f.Metadata["requested_version"] = pkg.RequestedVersion
+					}
+
+					var title string
+					switch class {
+					case model.ClassDeprecated:
+						f.Severity = model.SeverityMedium
+						for _, reason := range pkg.DeprecationReasons {
+							if strings.EqualFold(reason, "CriticalBugs") {
+								f.Severity = model.SeverityHigh
+							}
+						}
+						f.Metadata["deprecation_reasons"] = pkg.DeprecationReasons
+						title = fmt.Sprintf("Deprecated (%s)", strings.Join(pkg.DeprecationReasons, ", "))
+						if alt := pkg.AlternativePackage; alt != nil && alt.ID != "" {
+							f.Metadata["alternative_package"] = alt.ID
+							f.Metadata["alternative_version_range"] = alt.VersionRange
+							title += fmt.Sprintf("; use %s %s instead", alt.ID, alt.VersionRange)
+						}
+					case model.ClassOutdated:
+						if pkg.LatestVersion == "" || pkg.LatestVersion == pkg.ResolvedVersion {
+							continue
+						}
+						f.Severity = model.SeverityLow
+						f.Metadata["latest_version"] = pkg.LatestVersion
+						title = fmt.Sprintf("Latest version is %s", pkg.LatestVersion)
+					}
+					f.Title = &title
+
+					findings = append(findings, f)
+				}
+			}
+		}
+	}
+
+	return findings, rep.Problems, nil
+}
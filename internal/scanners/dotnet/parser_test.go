@@ -65,3 +65,107 @@ func TestParseDotnetOutput_Mixed(t *testing.T) {
 		t.Error("expected 1 Low")
 	}
 }
+
+func TestParseDotnetOutput_ProjectAndFramework(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "dotnet_output_mixed_severity.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParseDotnetOutput(string(data), "test.sln")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %d", len(findings))
+	}
+
+	first := findings[0]
+	if first.Metadata["project"] != "MixedApp" || first.Metadata["framework"] != "netcoreapp3.1" {
+		t.Errorf("expected project and framework headers, got %v", first.Metadata)
+	}
+	if first.Metadata["top_level"] != true || findings[2].Metadata["top_level"] != false {
+		t.Errorf("expected top-level then transitive, got %v / %v", first.Metadata["top_level"], findings[2].Metadata["top_level"])
+	}
+}
+
+func TestParseDotnetJSON(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "dotnet_output.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, problems, err := ParseDotnetJSON(string(data), "test.sln")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(problems) != 2 || problems[0].Project != "/src/Legacy/Legacy.csproj" {
+		t.Errorf("unexpected problems: %+v", problems)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+
+	top := findings[0]
+	if top.Package != "Newtonsoft.Json" || top.VulnerabilityID != "GHSA-5crp-9r3c-p9vr" || top.Severity != model.SeverityHigh {
+		t.Errorf("unexpected top-level finding: %s %s %s", top.Package, top.VulnerabilityID, top.Severity)
+	}
+	if top.Metadata["project"] != "/src/Web/Web.csproj" || top.Metadata["framework"] != "net8.0" {
+		t.Errorf("expected project path and framework, got %v", top.Metadata)
+	}
+	if top.Metadata["top_level"] != true || top.Metadata["requested_version"] != "12.0.1" {
+		t.Errorf("expected top-level requested version, got %v", top.Metadata)
+	}
+
+	transitive := findings[1]
+	if transitive.Metadata["top_level"] != false || transitive.InstalledVersion != "4.5.0" {
+		t.Errorf("unexpected transitive finding: %v %s", transitive.Metadata["top_level"], transitive.InstalledVersion)
+	}
+	if _, ok := transitive.Metadata["requested_version"]; ok {
+		t.Error("transitive packages have no requested version")
+	}
+	if transitive.Severity != model.SeverityCritical {
+		t.Errorf("expected critical, got %s", transitive.Severity)
+	}
+}
+
+func TestParseDotnetHealthJSON(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "dotnet_deprecated.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deprecated, _, err := ParseDotnetHealthJSON(string(data), "test.sln", model.ClassDeprecated)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(deprecated) != 2 {
+		t.Fatalf("expected 2 deprecated findings, got %d", len(deprecated))
+	}
+	blob := deprecated[0]
+	if blob.IsVulnerability() || blob.Class != model.ClassDeprecated || blob.Severity != model.SeverityMedium {
+		t.Errorf("unexpected class/severity: %q %s", blob.Class, blob.Severity)
+	}
+	if blob.Metadata["alternative_package"] != "Azure.Storage.Blobs" {
+		t.Errorf("expected alternative package, got %v", blob.Metadata)
+	}
+	if deprecated[1].Severity != model.SeverityHigh {
+		t.Errorf("expected CriticalBugs deprecation to be high, got %s", deprecated[1].Severity)
+	}
+
+	data, err = os.ReadFile(filepath.Join("testdata", "dotnet_outdated.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	outdated, _, err := ParseDotnetHealthJSON(string(data), "test.sln", model.ClassOutdated)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(outdated) != 1 {
+		t.Fatalf("expected 1 outdated finding (up-to-date packages skipped), got %d", len(outdated))
+	}
+	if outdated[0].Metadata["latest_version"] != "4.0.0" || outdated[0].VulnerabilityID != "outdated:Serilog" {
+		t.Errorf("unexpected outdated finding: %s %v", outdated[0].VulnerabilityID, outdated[0].Metadata)
+	}
+}
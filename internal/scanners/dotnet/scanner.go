@@ -2,124 +2,334 @@ package dotnet
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"depscanity/internal/detect"
 	depExec "depscanity/internal/exec"
 	"depscanity/internal/model"
 	"depscanity/internal/report"
+	"depscanity/internal/scanners"
 )
 
 const MaxDotnetSolutions = 5
 
-// ScanDotnet executes dotnet list package --vulnerable and parses the results.
-func ScanDotnet(ctx context.Context, rootPath string, detection detect.DetectionResult, timeoutSec int, outDir string) ([]model.Finding, []report.ScannerError) {
+// Scanner lists vulnerable NuGet packages for solutions and projects.
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the dotnet scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "dotnet" }
+
+func (s *Scanner) MaxTargets() int { return MaxDotnetSolutions }
+
+// LockGroup keeps restores one at a time: a solution and its orphan projects can share obj/ folders.
+func (s *Scanner) LockGroup(target string) string { return "dotnet" }
+
+// Targets prefers solutions, adds projects not included in any solution (orphans)
+// and falls back to the repository root when only an obj folder is present.
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	if len(det.Dotnet) == 0 {
+		// Heuristic: an obj folder implies a dotnet project without recognized project files
+		if _, err := os.Stat(filepath.Join(s.opts.Root, "obj")); err == nil {
+			fmt.Printf("  No solutions or projects found, scanning repository root.\n")
+			return []string{s.opts.Root}
+		}
+		return nil
+	}
+
+	// A solution migrated with "dotnet sln migrate" keeps its .sln next to the new .slnx;
+	// scan only the .slnx so the same projects are not restored and listed twice
+	slnx := make(map[string]bool)
+	for _, f := range det.Dotnet {
+		if strings.EqualFold(filepath.Ext(f), ".slnx") {
+			slnx[strings.ToLower(strings.TrimSuffix(f, filepath.Ext(f)))] = true
+		}
+	}
+	var slns []string
+	for _, f := range det.Dotnet {
+		if !detect.IsDotnetSolution(f) {
+			continue
+		}
+		if strings.EqualFold(filepath.Ext(f), ".sln") && slnx[strings.ToLower(strings.TrimSuffix(f, filepath.Ext(f)))] {
+			fmt.Printf("  Skipping %s (%s.slnx supersedes it).\n", f, strings.TrimSuffix(filepath.Base(f), filepath.Ext(f)))
+			continue
+		}
+		slns = append(slns, f)
+	}
+
+	// Fallback: If no SLNs, use project files (csproj, fsproj, vbproj) directly
+	if len(slns) == 0 {
+		fmt.Printf("  Targeting %d projects (no solution found).\n", len(det.Dotnet))
+		return det.Dotnet
+	}
+
+	targets := append([]string{}, slns...)
+
+	// Detect orphans
+	includedProjects, err := getProjectsInSolutions(slns)
+	if err != nil {
+		// Warn but proceed with just solutions
+		fmt.Printf("Warning: Failed to parse solutions for orphan detection: %v\n", err)
+	} else {
+		orphanCount := 0
+		for _, projPath := range det.Dotnet {
+			if detect.IsDotnetSolution(projPath) {
+				continue
+			}
+			if !includedProjects[projPath] {
+				targets = append(targets, projPath)
+				orphanCount++
+			}
+		}
+		if orphanCount > 0 {
+			fmt.Printf("  Found %d orphan projects (not in solution).\n", orphanCount)
+		}
+	}
+
+	fmt.Printf("  Targeting %d items (%d solutions + orphans).\n", len(targets), len(slns))
+	return targets
+}
+
+// Scan restores the target and runs dotnet list package --vulnerable on it.
+func (s *Scanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	return ScanDotnet(ctx, target, s.opts)
+}
+
+// ScanDotnet executes dotnet list package --vulnerable for a single solution, project or directory.
+func ScanDotnet(ctx context.Context, target string, opts scanners.Options) ([]model.Finding, []report.ScannerError) {
 	var findings []model.Finding
 	var scannerErrors []report.ScannerError
 
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
+	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
+		scannerErrors = append(scannerErrors, report.ScannerError{
+			Source:   "dotnet",
+			Location: target,
+			Message:  fmt.Sprintf("failed to create raw output dir: %v", err),
+		})
+		return findings, scannerErrors
+	}
+	sanitized := sanitizePath(target)
+
 	// 1. Check dotnet existence
 	if _, err := exec.LookPath("dotnet"); err != nil {
+		// Existing restore output still yields an inventory
+		writeInventory(rawOutDir, sanitized, loadInventories(target))
 		scannerErrors = append(scannerErrors, report.ScannerError{
 			Source:   "dotnet",
-			Location: rootPath,
+			Location: target,
 			Message:  "dotnet executable not found in PATH",
 		})
 		return findings, scannerErrors
 	}
 
-	targets := detection.Dotnet
-	// If no solutions found, try scanning the root (fallback)
-	if len(targets) == 0 {
-		targets = []string{rootPath}
-	} else if len(targets) > MaxDotnetSolutions {
+	// Try to restore first (best-effort, but usually required for accurate results)
+	wd := target
+	info, err := os.Stat(target)
+	if err == nil && !info.IsDir() {
+		wd = filepath.Dir(target)
+	}
+
+	restoreArgs := []string{"restore"}
+	if err == nil && !info.IsDir() {
+		restoreArgs = append(restoreArgs, target)
+	}
+
+	// Run restore
+	// We treat it as best-effort. If it fails, we still try to list (it might fail too, but we let it handle that).
+	// We don't want to abort if restore fails (maybe user has private feeds or auth issues, but local cache is enough?)
+	restoreRes, restoreErr := opts.RunPhase(ctx, "dotnet", scanners.PhaseRestore, target, "dotnet", restoreArgs, wd)
+	if restoreRes.ExitCode == 124 {
+		fmt.Printf("  [Dotnet] Restore timed out for %s (%s), attempting scan anyway\n", target, scanners.TimeoutCause(ctx, scanners.PhaseRestore))
+	} else if restoreErr != nil {
+		fmt.Printf("  [Dotnet] Restore failed for %s (attempting scan anyway): %v\n", target, restoreErr)
+	}
+
+	// Native inventory from the restore output (or packages.lock.json)
+	inventories := loadInventories(target)
+	writeInventory(rawOutDir, sanitized, inventories)
+
+	args := []string{"list"}
+	// If target is a file (sln/slnx/*proj), pass it. If directory (root fallback), pass nothing (implies CWD)
+	if err == nil && !info.IsDir() {
+		args = append(args, target)
+	}
+
+	args = append(args, "package")
+	listArgs := append([]string{}, args...)
+	args = append(args, "--vulnerable", "--include-transitive")
+
+	// Prefer the JSON report (SDK 7.0.200+); older SDKs reject --format and get the text table.
+	jsonArgs := append(append([]string{}, args...), "--format", "json", "--output-version", "1")
+	res, err := opts.RunPhase(ctx, "dotnet", scanners.PhaseAudit, target, "dotnet", jsonArgs, wd)
+	jsonOutput := res.ExitCode != 124 && res.ExitCode != 127 && strings.HasPrefix(strings.TrimSpace(res.Stdout), "{")
+	textFallback := !jsonOutput && formatRejected(res)
+	if textFallback {
+		fmt.Printf("  [Dotnet] JSON output not supported for %s, falling back to text output\n", target)
+		res, err = opts.RunPhase(ctx, "dotnet", scanners.PhaseAudit, target, "dotnet", args, wd)
+	}
+	if res.ExitCode == 124 {
 		scannerErrors = append(scannerErrors, report.ScannerError{
 			Source:   "dotnet",
-			Location: rootPath,
-			Message:  fmt.Sprintf("More than %d solutions found; scanning limited subset", MaxDotnetSolutions),
+			Location: target,
+			Message:  fmt.Sprintf("dotnet list package timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit)),
 		})
-		targets = targets[:MaxDotnetSolutions]
+		return findings, scannerErrors
 	}
-
-	rawOutDir := filepath.Join(outDir, "raw")
-	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
+	if res.ExitCode == 127 {
 		scannerErrors = append(scannerErrors, report.ScannerError{
 			Source:   "dotnet",
-			Location: rootPath,
-			Message:  fmt.Sprintf("failed to create raw output dir: %v", err),
+			Location: target,
+			Message:  fmt.Sprintf("dotnet execution failed (code %d): %v", res.ExitCode, err),
+		})
+		return findings, scannerErrors
+	}
+	if !jsonOutput && !textFallback {
+		// The SDK accepted --format but produced no report (restore or project errors)
+		scannerErrors = append(scannerErrors, report.ScannerError{
+			Source:   "dotnet",
+			Location: target,
+			Message:  fmt.Sprintf("dotnet list package failed (code %d): %s", res.ExitCode, firstLine(res.Stderr, res.Stdout)),
 		})
 		return findings, scannerErrors
 	}
 
-	for _, target := range targets {
-		// Try to restore first (best-effort, but usually required for accurate results)
-		// We use the same timeout? Or a shorter one? Restore can be slow.
-		// Let's deduce WD
-		wd := target
-		info, err := os.Stat(target)
-		if err == nil && !info.IsDir() {
-			wd = filepath.Dir(target)
-		}
+	// Save raw output
+	ext := "txt"
+	if jsonOutput {
+		ext = "json"
+	}
+	rawFile := filepath.Join(rawOutDir, fmt.Sprintf("dotnet-%s.%s", sanitized, ext))
+	_ = os.WriteFile(rawFile, []byte(res.Stdout), 0644)
 
-		restoreArgs := []string{"restore"}
-		if err == nil && !info.IsDir() {
-			restoreArgs = append(restoreArgs, target)
+	// Parse
+	if jsonOutput {
+		var problems []Problem
+		findings, problems, err = ParseDotnetJSON(res.Stdout, target)
+		for _, p := range problems {
+			if !strings.EqualFold(p.Level, "error") {
+				continue
+			}
+			location := p.Project
+			if location == "" {
+				location = target
+			}
+			scannerErrors = append(scannerErrors, report.ScannerError{
+				Source:   "dotnet",
+				Location: location,
+				Message:  p.Text,
+			})
 		}
+	} else {
+		findings, err = ParseDotnetOutput(res.Stdout, target)
+	}
+	if err != nil {
+		scannerErrors = append(scannerErrors, report.ScannerError{
+			Source:   "dotnet",
+			Location: target,
+			Message:  fmt.Sprintf("parse error: %v", err),
+		})
+	}
 
-		// Run restore
-		// We treat it as best-effort. If it fails, we still try to list (it might fail too, but we let it handle that).
-		// We don't want to abort if restore fails (maybe user has private feeds or auth issues, but local cache is enough?)
-		// Actually user said "I had to do before dotnet restore", implying it's needed.
-		_, restoreErr := depExec.Run(ctx, "dotnet", restoreArgs, wd)
-		if restoreErr != nil {
-			// Just log to stdout for now or capture as non-fatal error?
-			// Provide visibility in stdout
-			fmt.Printf("  [Dotnet] Restore failed for %s (attempting scan anyway): %v\n", target, restoreErr)
+	// Opt-in package health (--nuget-health): deprecated and outdated packages
+	if opts.NugetHealth {
+		if jsonOutput {
+			healthFindings, healthErrors := listPackageHealth(ctx, target, listArgs, wd, rawOutDir, sanitized, opts)
+			findings = append(findings, healthFindings...)
+			scannerErrors = append(scannerErrors, healthErrors...)
+		} else {
+			scannerErrors = append(scannerErrors, report.ScannerError{
+				Source:   "dotnet",
+				Location: target,
+				Message:  "--nuget-health needs JSON output from dotnet list package (.NET SDK 7.0.200 or later)",
+			})
 		}
+	}
 
-		args := []string{"list"}
-		// If target is a file (sln/csproj), pass it. If directory (root fallback), pass nothing (implies CWD or we pass dir)
-		if err == nil && !info.IsDir() {
-			args = append(args, target)
-		}
+	annotateChains(findings, inventories)
+	annotateCentralPins(findings, target, inventories)
+
+	return findings, scannerErrors
+}
 
-		args = append(args, "package", "--vulnerable", "--include-transitive")
+// listPackageHealth runs dotnet list package --deprecated and --outdated for the target.
+// listArgs is the "list [target] package" prefix.
+func listPackageHealth(ctx context.Context, target string, listArgs []string, wd, rawOutDir, sanitized string, opts scanners.Options) ([]model.Finding, []report.ScannerError) {
+	var findings []model.Finding
+	var scannerErrors []report.ScannerError
 
-		// wd is already calculated above for restore
+	for _, class := range []model.FindingClass{model.ClassDeprecated, model.ClassOutdated} {
+		args := append(append([]string{}, listArgs...), "--"+string(class), "--format", "json", "--output-version", "1")
+		if class == model.ClassDeprecated {
+			// Transitive deprecations matter; transitive "outdated" is mostly noise
+			args = append(args, "--include-transitive")
+		}
 
-		res, err := depExec.Run(ctx, "dotnet", args, wd)
-		if res.ExitCode == 127 || res.ExitCode == 124 {
+		res, err := opts.RunPhase(ctx, "dotnet", scanners.PhaseAudit, target, "dotnet", args, wd)
+		if res.ExitCode == 124 {
 			scannerErrors = append(scannerErrors, report.ScannerError{
 				Source:   "dotnet",
 				Location: target,
-				Message:  fmt.Sprintf("dotnet execution failed (code %d): %v", res.ExitCode, err),
+				Message:  fmt.Sprintf("dotnet list package --%s timed out after %s (%s)", class, res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit)),
 			})
 			continue
 		}
 
-		// Save raw output
-		sanitized := sanitizePath(target)
-		rawFile := filepath.Join(rawOutDir, fmt.Sprintf("dotnet-%s.txt", sanitized))
+		rawFile := filepath.Join(rawOutDir, fmt.Sprintf("dotnet-%s-%s.json", class, sanitized))
 		_ = os.WriteFile(rawFile, []byte(res.Stdout), 0644)
 
-		// Parse
-		f, err := ParseDotnetOutput(res.Stdout, target)
+		if !strings.HasPrefix(strings.TrimSpace(res.Stdout), "{") {
+			scannerErrors = append(scannerErrors, report.ScannerError{
+				Source:   "dotnet",
+				Location: target,
+				Message:  fmt.Sprintf("dotnet list package --%s failed (code %d): %v", class, res.ExitCode, err),
+			})
+			continue
+		}
+
+		classFindings, problems, err := ParseDotnetHealthJSON(res.Stdout, target, class)
 		if err != nil {
 			scannerErrors = append(scannerErrors, report.ScannerError{
 				Source:   "dotnet",
 				Location: target,
-				Message:  fmt.Sprintf("parse error: %v", err),
+				Message:  fmt.Sprintf("parse error (--%s): %v", class, err),
 			})
+			continue
+		}
+		for _, p := range problems {
+			if strings.EqualFold(p.Level, "error") {
+				fmt.Printf("  [Dotnet] --%s: %s\n", class, p.Text)
+			}
 		}
-		findings = append(findings, f...)
+		findings = append(findings, classFindings...)
 	}
 
 	return findings, scannerErrors
 }
 
+// writeInventory saves the per-project NuGet inventory next to the raw tool output.
+func writeInventory(rawOutDir, sanitized string, inventories []*Inventory) {
+	if len(inventories) == 0 {
+		return
+	}
+	data, err := json.MarshalIndent(inventories, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(rawOutDir, fmt.Sprintf("dotnet-inventory-%s.json", sanitized)), data, 0644)
+}
+
 func sanitizePath(path string) string {
 	s := strings.Map(func(r rune) rune {
 		if r == '/' || r == '\\' || r == ':' || r == ' ' {
@@ -129,3 +339,28 @@ func sanitizePath(path string) string {
 	}, path)
 	return strings.Trim(s, "_")
 }
+
+// formatRejected reports whether dotnet list package failed because the SDK predates
+// --format json (.NET SDK before 7.0.200 answers "Unrecognized option '--format'").
+func formatRejected(res depExec.Result) bool {
+	if res.ExitCode == 0 {
+		return false
+	}
+	out := strings.ToLower(res.Stderr + "\n" + res.Stdout)
+	if !strings.Contains(out, "--format") && !strings.Contains(out, "--output-version") {
+		return false
+	}
+	return strings.Contains(out, "unrecognized") || strings.Contains(out, "unknown") || strings.Contains(out, "not supported")
+}
+
+// firstLine returns the first non-empty line of the given outputs, for error messages.
+func firstLine(outputs ...string) string {
+	for _, out := range outputs {
+		for _, line := range strings.Split(out, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				return line
+			}
+		}
+	}
+	return "no output"
+}
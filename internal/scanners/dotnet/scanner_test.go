@@ -0,0 +1,49 @@
+package dotnet
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"depscanity/internal/detect"
+	depExec "depscanity/internal/exec"
+	"depscanity/internal/scanners"
+)
+
+func TestTargets_SlnxSupersedesSln(t *testing.T) {
+	dir := t.TempDir()
+	app, appx, other := filepath.Join(dir, "App.sln"), filepath.Join(dir, "App.slnx"), filepath.Join(dir, "Other.sln")
+	for _, f := range []string{app, other} {
+		if err := os.WriteFile(f, []byte("Microsoft Visual Studio Solution File, Format Version 12.00\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(appx, []byte("<Solution />\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Scanner{opts: scanners.Options{Root: dir}}
+	got := s.Targets(detect.DetectionResult{Dotnet: []string{app, appx, other}})
+	if want := []string{appx, other}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Targets = %v, want %v", got, want)
+	}
+}
+
+func TestFormatRejected(t *testing.T) {
+	cases := []struct {
+		name string
+		res  depExec.Result
+		want bool
+	}{
+		{"old SDK", depExec.Result{ExitCode: 1, Stderr: "error: Unrecognized option '--format'\n"}, true},
+		{"old SDK on stdout", depExec.Result{ExitCode: 1, Stdout: "Specify --help for a list of available options and commands.\nUnrecognized option '--output-version'"}, true},
+		{"restore error", depExec.Result{ExitCode: 1, Stdout: "error: No assets file was found for App.csproj. Please run a NuGet package restore."}, false},
+		{"success", depExec.Result{Stdout: `{"version": 1}`}, false},
+	}
+	for _, c := range cases {
+		if got := formatRejected(c.res); got != c.want {
+			t.Errorf("%s: formatRejected = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
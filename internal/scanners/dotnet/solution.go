@@ -0,0 +1,99 @@
+package dotnet
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// getProjectsInSolutions parses .sln and .slnx files to find included projects.
+// Returns a map of absolute paths to projects that are PART of a solution.
+func getProjectsInSolutions(slnPaths []string) (map[string]bool, error) {
+	included := make(map[string]bool)
+
+	for _, slnPath := range slnPaths {
+		if strings.HasSuffix(strings.ToLower(slnPath), ".slnx") {
+			if err := getProjectsInSlnx(slnPath, included); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		file, err := os.Open(slnPath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		baseDir := filepath.Dir(slnPath)
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			// Format: Project("{GUID}") = "Name", "Path\To\Project.csproj", "{GUID}"
+			if strings.HasPrefix(line, "Project(") {
+				parts := strings.Split(line, "=")
+				if len(parts) >= 2 {
+					// valid project line
+					// split by comma to get the path (2nd quoted string)
+					segments := strings.Split(parts[1], ",")
+					if len(segments) >= 3 {
+						// The path is in the second segment, quoted.
+						rawPath := strings.TrimSpace(segments[1])
+						rawPath = strings.Trim(rawPath, "\"")
+
+						// SLN uses backslashes, convert to OS specific separators
+						cleanPath := strings.ReplaceAll(rawPath, "\\", string(os.PathSeparator))
+
+						absPath := filepath.Join(baseDir, cleanPath)
+
+						// Normalize path
+						absPath, _ = filepath.Abs(absPath)
+						included[absPath] = true
+					}
+				}
+			}
+		}
+	}
+	return included, nil
+}
+
+// getProjectsInSlnx adds the projects of an XML solution to included.
+// Format: <Solution><Folder Name="/src/"><Project Path="src/App/App.csproj" /></Folder></Solution>
+func getProjectsInSlnx(slnxPath string, included map[string]bool) error {
+	file, err := os.Open(slnxPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	baseDir := filepath.Dir(slnxPath)
+	decoder := xml.NewDecoder(file)
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", slnxPath, err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "Project" {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local != "Path" {
+				continue
+			}
+			// Paths may use either separator
+			cleanPath := strings.ReplaceAll(attr.Value, "\\", string(os.PathSeparator))
+			cleanPath = strings.ReplaceAll(cleanPath, "/", string(os.PathSeparator))
+			absPath, _ := filepath.Abs(filepath.Join(baseDir, cleanPath))
+			included[absPath] = true
+		}
+	}
+}
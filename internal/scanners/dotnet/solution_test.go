@@ -0,0 +1,34 @@
+package dotnet
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetProjectsInSolutions_Slnx(t *testing.T) {
+	slnx, err := filepath.Abs(filepath.Join("testdata", "Modern", "App.slnx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	included, err := getProjectsInSolutions([]string{slnx})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	base := filepath.Dir(slnx)
+	expected := []string{
+		filepath.Join(base, "src", "Api", "Api.csproj"),
+		filepath.Join(base, "src", "Domain", "Domain.fsproj"),
+		filepath.Join(base, "tests", "Api.Tests", "Api.Tests.vbproj"),
+		filepath.Join(base, "tools", "Seed", "Seed.csproj"),
+	}
+	if len(included) != len(expected) {
+		t.Fatalf("expected %d projects, got %v", len(expected), included)
+	}
+	for _, p := range expected {
+		if !included[p] {
+			t.Errorf("expected %s in solution, got %v", p, included)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package govulncheck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"depscanity/internal/model"
+	"depscanity/internal/scanners/osv"
+)
+
+// Reachability levels reported in Finding.Metadata["reachability"], from strongest to weakest.
+const (
+	// ReachabilityCalled means a vulnerable symbol is reachable from the module's code.
+	ReachabilityCalled = "called"
+	// ReachabilityImported means a vulnerable package is imported but no vulnerable symbol is called.
+	ReachabilityImported = "imported"
+	// ReachabilityRequired means the vulnerable module is only required in go.mod.
+	ReachabilityRequired = "required"
+)
+
+// Message is one entry of the `govulncheck -json` stream.
+type Message struct {
+	OSV     *osv.Vulnerability `json:"osv,omitempty"`
+	Finding *Finding           `json:"finding,omitempty"`
+}
+
+// Finding is a govulncheck finding: the trace starts at the vulnerable symbol
+// and, for called findings, walks back to the module's own code.
+type Finding struct {
+	OSV          string  `json:"osv"`
+	FixedVersion string  `json:"fixed_version"`
+	Trace        []Frame `json:"trace"`
+}
+
+type Frame struct {
+	Module   string    `json:"module"`
+	Version  string    `json:"version"`
+	Package  string    `json:"package"`
+	Function string    `json:"function"`
+	Receiver string    `json:"receiver"`
+	Position *Position `json:"position"`
+}
+
+type Position struct {
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
+}
+
+// ParseGovulncheckOutput parses the streamed JSON messages of `govulncheck -json ./...`.
+// Findings for the same advisory and module are merged, keeping the strongest reachability.
+func ParseGovulncheckOutput(output string, modPath string) ([]model.Finding, error) {
+	var findings []model.Finding
+	if strings.TrimSpace(output) == "" {
+		return findings, nil
+	}
+
+	entries := make(map[string]*osv.Vulnerability)
+	type key struct{ id, module, version string }
+	best := make(map[key]*Finding)
+	var order []key
+
+	dec := json.NewDecoder(strings.NewReader(output))
+	for {
+		var msg Message
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode govulncheck json: %w", err)
+		}
+
+		if msg.OSV != nil {
+			entries[msg.OSV.ID] = msg.OSV
+		}
+		if msg.Finding == nil || len(msg.Finding.Trace) == 0 {
+			continue
+		}
+
+		f := msg.Finding
+		k := key{f.OSV, f.Trace[0].Module, f.Trace[0].Version}
+		prev, exists := best[k]
+		if !exists {
+			order = append(order, k)
+			best[k] = f
+		} else if rank(reachability(f)) > rank(reachability(prev)) {
+			best[k] = f
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].module != order[j].module {
+			return order[i].module < order[j].module
+		}
+		return order[i].id < order[j].id
+	})
+
+	for _, k := range order {
+		f := best[k]
+		level := reachability(f)
+
+		title := f.OSV
+		var urlPtr *string
+		var aliases []string
+		if entry, ok := entries[f.OSV]; ok {
+			if entry.Summary != "" {
+				title = entry.Summary
+			}
+			aliases = entry.Aliases
+			if entry.DatabaseSpecific.URL != "" {
+				url := entry.DatabaseSpecific.URL
+				urlPtr = &url
+			}
+		}
+		if urlPtr == nil {
+			url := "https://pkg.go.dev/vuln/" + f.OSV
+			urlPtr = &url
+		}
+
+		var fixedPtr *string
+		if f.FixedVersion != "" {
+			fixed := f.FixedVersion
+			fixedPtr = &fixed
+		}
+
+		metadata := map[string]any{
+			"reachability": level,
+			"reachable":    level == ReachabilityCalled,
+			"aliases":      aliases,
+		}
+		if level != ReachabilityRequired {
+			metadata["package"] = f.Trace[0].Package
+		}
+		if level == ReachabilityCalled {
+			metadata["call_trace"] = callTrace(f.Trace)
+		}
+
+		findings = append(findings, model.Finding{
+			Source:           "govulncheck",
+			Ecosystem:        "go",
+			Package:          k.module,
+			InstalledVersion: k.version,
+			FixedVersion:     fixedPtr,
+			VulnerabilityID:  f.OSV,
+			Severity:         model.SeverityUnknown,
+			Title:            &title,
+			URL:              urlPtr,
+			Location:         modPath,
+			Metadata:         metadata,
+		})
+	}
+
+	return findings, nil
+}
+
+func reachability(f *Finding) string {
+	switch {
+	case f.Trace[0].Function != "":
+		return ReachabilityCalled
+	case f.Trace[0].Package != "":
+		return ReachabilityImported
+	default:
+		return ReachabilityRequired
+	}
+}
+
+func rank(level string) int {
+	switch level {
+	case ReachabilityCalled:
+		return 3
+	case ReachabilityImported:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// callTrace renders the trace from the module's code down to the vulnerable symbol,
+// e.g. "example.com/app.main (main.go:12)".
+func callTrace(trace []Frame) []string {
+	frames := make([]string, 0, len(trace))
+	for i := len(trace) - 1; i >= 0; i-- {
+		fr := trace[i]
+		name := fr.Package + "." + fr.Function
+		if fr.Receiver != "" {
+			name = fr.Package + "." + strings.TrimPrefix(fr.Receiver, "*") + "." + fr.Function
+		}
+		if fr.Position != nil && fr.Position.Filename != "" {
+			name = fmt.Sprintf("%s (%s:%d)", name, fr.Position.Filename, fr.Position.Line)
+		}
+		frames = append(frames, name)
+	}
+	return frames
+}
@@ -0,0 +1,60 @@
+package govulncheck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"depscanity/internal/model"
+)
+
+func TestParseGovulncheckOutput(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "govulncheck_output.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParseGovulncheckOutput(string(data), "/repo/go.mod")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings (module, package and symbol findings merged), got %d", len(findings))
+	}
+
+	net := findings[0]
+	if net.Package != "golang.org/x/net" || net.VulnerabilityID != "GO-2023-1571" {
+		t.Errorf("unexpected first finding: %s %s", net.Package, net.VulnerabilityID)
+	}
+	if net.Metadata["reachability"] != ReachabilityRequired || net.Metadata["reachable"] != false {
+		t.Errorf("expected module-only finding, got %v", net.Metadata)
+	}
+	if net.Severity != model.SeverityUnknown {
+		t.Errorf("expected reachability to leave the severity unknown, got %s", net.Severity)
+	}
+	if net.URL == nil || *net.URL != "https://pkg.go.dev/vuln/GO-2023-1571" {
+		t.Errorf("expected pkg.go.dev fallback URL, got %v", net.URL)
+	}
+
+	text := findings[1]
+	if text.Ecosystem != "go" || text.Source != "govulncheck" || text.InstalledVersion != "v0.3.7" {
+		t.Errorf("unexpected identity: %s/%s %s", text.Source, text.Ecosystem, text.InstalledVersion)
+	}
+	if text.FixedVersion == nil || *text.FixedVersion != "v0.3.8" {
+		t.Errorf("expected fixed version v0.3.8, got %v", text.FixedVersion)
+	}
+	if text.Metadata["reachability"] != ReachabilityCalled || text.Metadata["reachable"] != true {
+		t.Errorf("expected symbol-level finding, got %v", text.Metadata)
+	}
+	if text.Severity != model.SeverityUnknown {
+		t.Errorf("expected reachability to leave the severity unknown, got %s", text.Severity)
+	}
+	trace, _ := text.Metadata["call_trace"].([]string)
+	if len(trace) != 2 || trace[0] != "example.com/app.main (main.go:12)" {
+		t.Errorf("unexpected call trace: %v", trace)
+	}
+	if text.Title == nil || *text.Title != "Denial of service via crafted Accept-Language header in golang.org/x/text/language" {
+		t.Errorf("expected summary from osv entry, got %v", text.Title)
+	}
+}
@@ -0,0 +1,101 @@
+package govulncheck
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+)
+
+// MaxModules caps how many Go modules are scanned per run.
+const MaxModules = 10
+
+// Scanner runs govulncheck in every detected Go module root.
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the govulncheck scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "govulncheck" }
+
+func (s *Scanner) MaxTargets() int { return MaxModules }
+
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	return det.Go
+}
+
+func (s *Scanner) Scan(ctx context.Context, modPath string) ([]model.Finding, []report.ScannerError) {
+	findings, err := ScanGovulncheck(ctx, modPath, s.opts)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "govulncheck",
+			Location: modPath,
+			Message:  err.Error(),
+		}}
+	}
+	return findings, nil
+}
+
+// ScanGovulncheck executes govulncheck -json ./... in the module root and parses the results.
+func ScanGovulncheck(ctx context.Context, modPath string, opts scanners.Options) ([]model.Finding, error) {
+	// 1. Setup paths
+	workDir := filepath.Dir(modPath)
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
+	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create raw output dir: %w", err)
+	}
+
+	// 2. Check govulncheck existence
+	if _, err := exec.LookPath("govulncheck"); err != nil {
+		return nil, fmt.Errorf("govulncheck executable not found in PATH")
+	}
+
+	// 3. Execution
+	// In JSON mode govulncheck exits 0 even when vulnerabilities are found;
+	// a non-zero exit means the module could not be loaded or analyzed.
+	res, err := opts.RunPhase(ctx, "govulncheck", scanners.PhaseAudit, modPath, "govulncheck", []string{"-json", "./..."}, workDir)
+	if res.ExitCode == 124 {
+		return nil, fmt.Errorf("govulncheck timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit))
+	}
+
+	// 4. Save raw output
+	sanitizedName := sanitizePath(workDir)
+	rawFile := filepath.Join(rawOutDir, fmt.Sprintf("govulncheck-%s.json", sanitizedName))
+	if err := os.WriteFile(rawFile, []byte(res.Stdout), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write raw output: %w", err)
+	}
+
+	if res.ExitCode != 0 {
+		return nil, fmt.Errorf("govulncheck failed (code %d): %v\nStderr: %s", res.ExitCode, err, res.Stderr)
+	}
+
+	// 5. Parse
+	findings, err := ParseGovulncheckOutput(res.Stdout, modPath)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	return findings, nil
+}
+
+func sanitizePath(path string) string {
+	s := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, path)
+	return strings.Trim(s, "_")
+}
@@ -0,0 +1,55 @@
+package jvm
+
+import (
+	"bufio"
+	"strings"
+)
+
+// LockedDependency is one module pinned in a gradle.lockfile.
+type LockedDependency struct {
+	Group          string
+	Artifact       string
+	Version        string
+	Configurations []string
+}
+
+// Name returns the maven coordinate group:artifact.
+func (d LockedDependency) Name() string {
+	return d.Group + ":" + d.Artifact
+}
+
+// ParseGradleLockfile parses a gradle.lockfile written by `gradle dependencies --write-locks`.
+// Lines look like `group:artifact:version=config1,config2`; comments and the
+// `empty=` line are skipped.
+func ParseGradleLockfile(content string) []LockedDependency {
+	var deps []LockedDependency
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		coords, configs, _ := strings.Cut(line, "=")
+		parts := strings.Split(coords, ":")
+		if len(parts) != 3 {
+			// e.g. "empty=annotationProcessor"
+			continue
+		}
+
+		dep := LockedDependency{
+			Group:    parts[0],
+			Artifact: parts[1],
+			Version:  parts[2],
+		}
+		for _, c := range strings.Split(configs, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				dep.Configurations = append(dep.Configurations, c)
+			}
+		}
+		deps = append(deps, dep)
+	}
+
+	return deps
+}
@@ -0,0 +1,127 @@
+package jvm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"depscanity/internal/model"
+)
+
+// trivyReport is the subset of `trivy fs --format json --list-all-pkgs` used here.
+type trivyReport struct {
+	Results []struct {
+		Target          string               `json:"Target"`
+		Type            string               `json:"Type"`
+		Packages        []trivyPackage       `json:"Packages"`
+		Vulnerabilities []trivyVulnerability `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+type trivyPackage struct {
+	ID      string `json:"ID"`
+	Name    string `json:"Name"`
+	Version string `json:"Version"`
+}
+
+type trivyVulnerability struct {
+	VulnerabilityID  string   `json:"VulnerabilityID"`
+	PkgID            string   `json:"PkgID"`
+	PkgName          string   `json:"PkgName"`
+	InstalledVersion string   `json:"InstalledVersion"`
+	FixedVersion     string   `json:"FixedVersion"`
+	Title            string   `json:"Title"`
+	Description      string   `json:"Description"`
+	Severity         string   `json:"Severity"`
+	PrimaryURL       string   `json:"PrimaryURL"`
+	References       []string `json:"References"`
+}
+
+// InventoryEntry is one resolved Maven artifact.
+type InventoryEntry struct {
+	Package   string `json:"package"`
+	Version   string `json:"version"`
+	Ecosystem string `json:"ecosystem"`
+	Target    string `json:"target"`
+}
+
+// ParseTrivyFsOutput parses trivy fs JSON for pom.xml / gradle.lockfile targets.
+// It returns the dependency inventory and the vulnerabilities as maven findings.
+func ParseTrivyFsOutput(output string, manifestPath string) ([]InventoryEntry, []model.Finding, error) {
+	var rep trivyReport
+	if err := json.Unmarshal([]byte(output), &rep); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal trivy json: %w", err)
+	}
+
+	var inventory []InventoryEntry
+	var findings []model.Finding
+
+	for _, result := range rep.Results {
+		for _, p := range result.Packages {
+			inventory = append(inventory, InventoryEntry{
+				Package:   p.Name,
+				Version:   p.Version,
+				Ecosystem: "maven",
+				Target:    result.Target,
+			})
+		}
+
+		for _, v := range result.Vulnerabilities {
+			sev, _ := model.ParseSeverity(v.Severity)
+
+			f := model.Finding{
+				Source:           "jvm",
+				Ecosystem:        "maven",
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				VulnerabilityID:  v.VulnerabilityID,
+				Severity:         sev,
+				Location:         manifestPath,
+				Metadata: map[string]any{
+					"build_tool": buildTool(result.Type),
+				},
+			}
+
+			// Trivy lists every fixed branch, e.g. "2.15.0, 2.12.2"
+			if v.FixedVersion != "" {
+				fixed := strings.TrimSpace(strings.Split(v.FixedVersion, ",")[0])
+				f.FixedVersion = &fixed
+				f.Metadata["fixed_versions"] = v.FixedVersion
+			}
+
+			title := v.Title
+			if title == "" {
+				title = v.VulnerabilityID
+			}
+			f.Title = &title
+
+			url := v.PrimaryURL
+			if url == "" && len(v.References) > 0 {
+				url = v.References[0]
+			}
+			if url != "" {
+				f.URL = &url
+			}
+
+			if v.Description != "" {
+				f.Metadata["description"] = v.Description
+			}
+
+			findings = append(findings, f)
+		}
+	}
+
+	return inventory, findings, nil
+}
+
+// buildTool maps the trivy result type to the build tool that declared the dependency.
+func buildTool(resultType string) string {
+	switch resultType {
+	case "pom":
+		return "maven"
+	case "gradle":
+		return "gradle"
+	default:
+		return resultType
+	}
+}
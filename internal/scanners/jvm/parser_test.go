@@ -0,0 +1,93 @@
+package jvm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+)
+
+func TestParseTrivyFsOutput(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "trivy_fs_output.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inventory, findings, err := ParseTrivyFsOutput(string(data), "/repo/billing/gradle.lockfile")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(inventory) != 3 || inventory[0].Package != "com.fasterxml.jackson.core:jackson-databind" {
+		t.Errorf("unexpected inventory: %+v", inventory)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+
+	log4j := findings[0]
+	if log4j.Source != "jvm" || log4j.Ecosystem != "maven" || log4j.Package != "org.apache.logging.log4j:log4j-core" {
+		t.Errorf("unexpected identity: %s/%s %s", log4j.Source, log4j.Ecosystem, log4j.Package)
+	}
+	if log4j.Severity != model.SeverityCritical {
+		t.Errorf("expected critical, got %s", log4j.Severity)
+	}
+	if log4j.FixedVersion == nil || *log4j.FixedVersion != "2.15.0" {
+		t.Errorf("expected fixed version 2.15.0, got %v", log4j.FixedVersion)
+	}
+	if log4j.Metadata["build_tool"] != "gradle" {
+		t.Errorf("expected gradle build tool, got %v", log4j.Metadata["build_tool"])
+	}
+
+	junit := findings[1]
+	if junit.FixedVersion != nil {
+		t.Errorf("expected no fixed version, got %v", *junit.FixedVersion)
+	}
+	if junit.Title == nil || *junit.Title != "CVE-2020-13949" {
+		t.Errorf("expected ID as title fallback, got %v", junit.Title)
+	}
+	if junit.URL == nil || *junit.URL != "https://github.com/advisories/GHSA-269g-pwp5-87pp" {
+		t.Errorf("expected reference URL fallback, got %v", junit.URL)
+	}
+}
+
+func TestParseGradleLockfile(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "gradle.lockfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deps := ParseGradleLockfile(string(data))
+	if len(deps) != 3 {
+		t.Fatalf("expected 3 locked dependencies, got %d", len(deps))
+	}
+	if deps[0].Name() != "com.fasterxml.jackson.core:jackson-databind" || deps[0].Version != "2.9.10.1" {
+		t.Errorf("unexpected first dependency: %+v", deps[0])
+	}
+	if len(deps[2].Configurations) != 2 || deps[2].Configurations[1] != "testRuntimeClasspath" {
+		t.Errorf("unexpected configurations: %v", deps[2].Configurations)
+	}
+}
+
+func TestTargetsPreferLockfile(t *testing.T) {
+	s := &Scanner{}
+	targets := s.Targets(detect.DetectionResult{Jvm: []string{
+		"/repo/billing/build.gradle.kts",
+		"/repo/billing/gradle.lockfile",
+		"/repo/orders/pom.xml",
+		"/repo/legacy/build.gradle",
+	}})
+
+	// legacy has no lockfile and is skipped
+	expected := []string{"/repo/billing/gradle.lockfile", "/repo/orders/pom.xml"}
+	if len(targets) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, targets)
+	}
+	for i := range expected {
+		if targets[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, targets)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package jvm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+	"depscanity/internal/scanners/trivy"
+)
+
+// MaxProjects caps how many Maven/Gradle projects are scanned per run.
+const MaxProjects = 10
+
+// Scanner inventories Maven and Gradle projects with trivy fs and reports maven findings.
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the JVM scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "jvm" }
+
+func (s *Scanner) LockGroup(target string) string { return trivy.LockGroup }
+
+func (s *Scanner) MaxTargets() int { return MaxProjects }
+
+// Targets picks one manifest per project directory.
+// gradle.lockfile is preferred over pom.xml; Gradle projects without dependency locking
+// (a bare build.gradle(.kts)) cannot be resolved offline and are skipped with a warning.
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	best := make(map[string]string)
+	for _, path := range det.Jvm {
+		dir := filepath.Dir(path)
+		if current, ok := best[dir]; !ok || manifestRank(path) < manifestRank(current) {
+			best[dir] = path
+		}
+	}
+
+	targets := make([]string, 0, len(best))
+	for _, path := range best {
+		if manifestRank(path) == 2 {
+			fmt.Printf("  [JVM] Skipping %s: no gradle.lockfile (enable dependency locking and run `gradle dependencies --write-locks`)\n", path)
+			continue
+		}
+		targets = append(targets, path)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+func manifestRank(path string) int {
+	switch strings.ToLower(filepath.Base(path)) {
+	case "gradle.lockfile":
+		return 0
+	case "pom.xml":
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (s *Scanner) Scan(ctx context.Context, manifestPath string) ([]model.Finding, []report.ScannerError) {
+	findings, err := ScanJvm(ctx, manifestPath, s.opts)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "jvm",
+			Location: manifestPath,
+			Message:  err.Error(),
+		}}
+	}
+	return findings, nil
+}
+
+// ScanJvm runs trivy fs restricted to a pom.xml or gradle.lockfile, writes the resolved
+// dependency inventory and parses the vulnerabilities into maven findings.
+func ScanJvm(ctx context.Context, manifestPath string, opts scanners.Options) ([]model.Finding, error) {
+	// 1. Setup paths
+	workDir := filepath.Dir(manifestPath)
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
+	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create raw output dir: %w", err)
+	}
+
+	// Gradle builds are only resolvable offline through dependency locking
+	base := strings.ToLower(filepath.Base(manifestPath))
+	if base == "build.gradle" || base == "build.gradle.kts" {
+		return nil, fmt.Errorf("no gradle.lockfile next to %s; enable dependency locking and run `gradle dependencies --write-locks`", filepath.Base(manifestPath))
+	}
+
+	// Configurations per locked module, used to annotate Gradle findings
+	var locked map[string]LockedDependency
+	if base == "gradle.lockfile" {
+		data, err := os.ReadFile(manifestPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gradle.lockfile: %w", err)
+		}
+		locked = make(map[string]LockedDependency)
+		for _, dep := range ParseGradleLockfile(string(data)) {
+			locked[dep.Name()+":"+dep.Version] = dep
+		}
+	}
+
+	// 2. Check trivy existence
+	if _, err := exec.LookPath("trivy"); err != nil {
+		return nil, fmt.Errorf("trivy executable not found in PATH")
+	}
+
+	// 3. Execution
+	// trivy fs on the manifest itself keeps the scan away from unrelated lockfiles in the tree
+	if err := trivy.CheckDB(opts); err != nil {
+		return nil, err
+	}
+	args := append([]string{"fs", "--scanners", "vuln", "--format", "json", "--list-all-pkgs", "--quiet"}, trivy.DBArgs(opts)...)
+	args = append(args, manifestPath)
+	res, err := opts.RunPhase(ctx, "jvm", scanners.PhaseAudit, manifestPath, "trivy", args, workDir)
+	if res.ExitCode == 124 {
+		return nil, fmt.Errorf("trivy fs timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit))
+	}
+	if res.ExitCode != 0 {
+		return nil, fmt.Errorf("trivy fs failed (code %d): %v\nStderr: %s", res.ExitCode, err, res.Stderr)
+	}
+
+	// 4. Save raw output and inventory
+	sanitizedName := sanitizePath(workDir)
+	rawFile := filepath.Join(rawOutDir, fmt.Sprintf("jvm-%s.json", sanitizedName))
+	if err := os.WriteFile(rawFile, []byte(res.Stdout), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write raw output: %w", err)
+	}
+
+	// 5. Parse
+	inventory, findings, err := ParseTrivyFsOutput(res.Stdout, manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	inventoryData, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode inventory: %w", err)
+	}
+	inventoryFile := filepath.Join(rawOutDir, fmt.Sprintf("jvm-inventory-%s.json", sanitizedName))
+	if err := os.WriteFile(inventoryFile, inventoryData, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write inventory: %w", err)
+	}
+
+	for i := range findings {
+		if dep, ok := locked[findings[i].Package+":"+findings[i].InstalledVersion]; ok {
+			findings[i].Metadata["configurations"] = dep.Configurations
+		}
+	}
+
+	return findings, nil
+}
+
+func sanitizePath(path string) string {
+	s := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, path)
+	return strings.Trim(s, "_")
+}
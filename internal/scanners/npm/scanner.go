@@ -7,16 +7,52 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
-	depExec "depscanity/internal/exec"
+	"depscanity/internal/detect"
 	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
 )
 
-// ScanNpm executes npm audit and parses the results.
-func ScanNpm(ctx context.Context, lockPath string, timeoutSec int, outDir string) ([]model.Finding, error) {
+// MaxLockfiles caps how many package-lock.json files are audited per run.
+const MaxLockfiles = 10
+
+// Scanner audits package-lock.json files with npm audit.
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the npm scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "npm" }
+
+func (s *Scanner) MaxTargets() int { return MaxLockfiles }
+
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	return det.Npm
+}
+
+func (s *Scanner) Scan(ctx context.Context, lockPath string) ([]model.Finding, []report.ScannerError) {
+	findings, err := ScanNpm(ctx, lockPath, s.opts)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "npm",
+			Location: lockPath,
+			Message:  err.Error(),
+		}}
+	}
+	return findings, nil
+}
+
+// ScanNpm executes npm ci (restore phase) and npm audit (audit phase) and parses the results.
+func ScanNpm(ctx context.Context, lockPath string, opts scanners.Options) ([]model.Finding, error) {
 	// 1. Setup paths
 	workDir := filepath.Dir(lockPath)
-	rawOutDir := filepath.Join(outDir, "raw")
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
 	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create raw output dir: %w", err)
 	}
@@ -27,24 +63,19 @@ func ScanNpm(ctx context.Context, lockPath string, timeoutSec int, outDir string
 	}
 
 	// 3. Execution (npm ci + npm audit)
-	// Optionally run npm ci (best effort)
-	// We use value of context or default timeout
-	// But Scan function accepts timeoutSec, so let's stick to ctx passed by caller which should have timeout
-	// However, we need to respect individual timeouts if we were managing it here.
-	// The prompt says "ScanNpm(ctx, lockPath, timeoutSec, outDir)".
-	// We will use the passed ctx primarily.
-
-	// Attempt npm ci (ignore error)
-	// Use a shorter timeout for ci? Or share the deadline?
-	// Let's assume ctx covers the whole operation.
-	depExec.Run(ctx, "npm", []string{"ci", "--ignore-scripts"}, workDir)
+	// Attempt npm ci (best effort, bounded by the restore budget)
+	ciRes, _ := opts.RunPhase(ctx, "npm", scanners.PhaseRestore, lockPath, "npm", []string{"ci", "--ignore-scripts"}, workDir)
+	if ciRes.ExitCode == 124 {
+		fmt.Printf("  [npm] npm ci timed out for %s (%s), auditing anyway\n", lockPath, scanners.TimeoutCause(ctx, scanners.PhaseRestore))
+	}
 
 	// Run npm audit
-	// We don't track duration specifically here as internal/exec does, but if we wanted to log it we could.
-	// For now, remove unused variable
-	res, err := depExec.Run(ctx, "npm", []string{"audit", "--json"}, workDir)
+	res, err := opts.RunPhase(ctx, "npm", scanners.PhaseAudit, lockPath, "npm", []string{"audit", "--json"}, workDir)
 	// npm audit returns non-zero if vulnerabilities found, so we must proceed unless it's a critical error (like missing executable or timeout)
-	if res.ExitCode == 127 || res.ExitCode == 124 {
+	if res.ExitCode == 124 {
+		return nil, fmt.Errorf("npm audit timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit))
+	}
+	if res.ExitCode == 127 {
 		return nil, fmt.Errorf("npm audit failed execution (code %d): %v", res.ExitCode, err)
 	}
 
@@ -0,0 +1,98 @@
+package osv
+
+import (
+	"math"
+	"strings"
+
+	"depscanity/internal/model"
+)
+
+// CVSSv3BaseScore computes the base score of a CVSS v3.x vector
+// (e.g. "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H").
+// RustSec advisories and the severity field of OSV entries only ship the vector, not the score.
+func CVSSv3BaseScore(vector string) (float64, bool) {
+	parts := strings.Split(vector, "/")
+	if len(parts) < 9 || !strings.HasPrefix(parts[0], "CVSS:3") {
+		return 0, false
+	}
+
+	metrics := make(map[string]string)
+	for _, p := range parts[1:] {
+		k, v, ok := strings.Cut(p, ":")
+		if !ok {
+			return 0, false
+		}
+		metrics[k] = v
+	}
+
+	scopeChanged := metrics["S"] == "C"
+	weights := map[string]map[string]float64{
+		"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+		"AC": {"L": 0.77, "H": 0.44},
+		"PR": {"N": 0.85, "L": 0.62, "H": 0.27},
+		"UI": {"N": 0.85, "R": 0.62},
+		"C":  {"H": 0.56, "L": 0.22, "N": 0},
+		"I":  {"H": 0.56, "L": 0.22, "N": 0},
+		"A":  {"H": 0.56, "L": 0.22, "N": 0},
+	}
+	if scopeChanged {
+		weights["PR"]["L"] = 0.68
+		weights["PR"]["H"] = 0.5
+	}
+
+	w := make(map[string]float64)
+	for metric, values := range weights {
+		v, ok := values[metrics[metric]]
+		if !ok {
+			return 0, false
+		}
+		w[metric] = v
+	}
+	if metrics["S"] != "U" && metrics["S"] != "C" {
+		return 0, false
+	}
+
+	iss := 1 - (1-w["C"])*(1-w["I"])*(1-w["A"])
+	var impact float64
+	if scopeChanged {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	} else {
+		impact = 6.42 * iss
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+
+	exploitability := 8.22 * w["AV"] * w["AC"] * w["PR"] * w["UI"]
+	if scopeChanged {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// roundUp implements the CVSS v3.1 Roundup function (smallest one-decimal value >= x).
+func roundUp(x float64) float64 {
+	i := int(math.Round(x * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return (math.Floor(float64(i)/10000) + 1) / 10
+}
+
+// VectorSeverity rates an entry from the highest CVSS v3 vector of its severity field,
+// or returns SeverityUnknown when it has none.
+func VectorSeverity(v Vulnerability) model.Severity {
+	best := -1.0
+	for _, s := range v.Severity {
+		if s.Type != "CVSS_V3" {
+			continue
+		}
+		if score, ok := CVSSv3BaseScore(s.Score); ok && score > best {
+			best = score
+		}
+	}
+	if best < 0 {
+		return model.SeverityUnknown
+	}
+	return SeverityFromScore(best)
+}
@@ -0,0 +1,25 @@
+package osv
+
+import "testing"
+
+func TestCVSSv3BaseScore(t *testing.T) {
+	tests := []struct {
+		vector string
+		score  float64
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8},
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:N/A:H", 5.9},
+		{"CVSS:3.0/AV:N/AC:L/PR:L/UI:N/S:C/C:L/I:L/A:N", 6.4},
+		{"CVSS:3.1/AV:L/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0},
+	}
+	for _, tt := range tests {
+		score, ok := CVSSv3BaseScore(tt.vector)
+		if !ok || score != tt.score {
+			t.Errorf("%s: expected %.1f, got %.1f (ok=%v)", tt.vector, tt.score, score, ok)
+		}
+	}
+
+	if _, ok := CVSSv3BaseScore("CVSS:2.0/AV:N"); ok {
+		t.Error("expected invalid vector to be rejected")
+	}
+}
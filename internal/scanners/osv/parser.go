@@ -0,0 +1,316 @@
+package osv
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"depscanity/internal/model"
+)
+
+// ScanOutput represents the JSON document produced by osv-scanner --json.
+type ScanOutput struct {
+	Results []SourceResult `json:"results"`
+}
+
+type SourceResult struct {
+	Source   SourceInfo     `json:"source"`
+	Packages []PackageVulns `json:"packages"`
+}
+
+type SourceInfo struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+type PackageVulns struct {
+	Package         PackageInfo     `json:"package"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+	Groups          []GroupInfo     `json:"groups"`
+}
+
+type PackageInfo struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+// GroupInfo lists vulnerability IDs osv-scanner considers the same issue.
+type GroupInfo struct {
+	IDs         []string `json:"ids"`
+	Aliases     []string `json:"aliases"`
+	MaxSeverity string   `json:"max_severity"`
+}
+
+// Vulnerability is the subset of the OSV schema DepScanity uses.
+type Vulnerability struct {
+	ID               string           `json:"id"`
+	Aliases          []string         `json:"aliases"`
+	Summary          string           `json:"summary"`
+	Details          string           `json:"details"`
+	Withdrawn        string           `json:"withdrawn"`
+	Affected         []Affected       `json:"affected"`
+	References       []Reference      `json:"references"`
+	Severity         []SeverityScore  `json:"severity"`
+	DatabaseSpecific DatabaseSpecific `json:"database_specific"`
+}
+
+// SeverityScore is a severity vector of an OSV entry, e.g. {"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/..."}.
+type SeverityScore struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type Affected struct {
+	Package  PackageInfo `json:"package"`
+	Ranges   []Range     `json:"ranges"`
+	Versions []string    `json:"versions"`
+}
+
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type DatabaseSpecific struct {
+	Severity string   `json:"severity"`
+	CweIDs   []string `json:"cwe_ids"`
+	URL      string   `json:"url"`
+}
+
+// ParseOsvOutput parses JSON output from `osv-scanner -r <path> --json`.
+// Vulnerabilities grouped as aliases of each other are reported once per package.
+func ParseOsvOutput(jsonOutput string) ([]model.Finding, error) {
+	var findings []model.Finding
+	if strings.TrimSpace(jsonOutput) == "" {
+		return findings, nil
+	}
+
+	var out ScanOutput
+	if err := json.Unmarshal([]byte(jsonOutput), &out); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal osv-scanner json: %w", err)
+	}
+
+	for _, result := range out.Results {
+		for _, pkg := range result.Packages {
+			seen := make(map[string]bool)
+			for _, v := range pkg.Vulnerabilities {
+				if seen[v.ID] {
+					continue
+				}
+
+				group := groupFor(pkg.Groups, v.ID)
+				aliases := mergeAliases(v, group)
+				for _, id := range group.IDs {
+					seen[id] = true
+				}
+				seen[v.ID] = true
+
+				findings = append(findings, newFinding(result.Source.Path, pkg.Package, v, group, aliases))
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+func newFinding(location string, pkg PackageInfo, v Vulnerability, group GroupInfo, aliases []string) model.Finding {
+	sev := severityFor(v, group)
+
+	title := v.Summary
+	if title == "" {
+		title = v.ID
+	}
+
+	var urlPtr *string
+	if url := advisoryURL(v); url != "" {
+		urlPtr = &url
+	}
+
+	var fixedPtr *string
+	if fixed := FixedVersion(v, pkg); fixed != "" {
+		fixedPtr = &fixed
+	}
+
+	return model.Finding{
+		Source:           "osv",
+		Ecosystem:        NormalizeEcosystem(pkg.Ecosystem),
+		Package:          pkg.Name,
+		InstalledVersion: pkg.Version,
+		FixedVersion:     fixedPtr,
+		VulnerabilityID:  v.ID,
+		Severity:         sev,
+		Title:            &title,
+		URL:              urlPtr,
+		Location:         location,
+		Metadata: map[string]any{
+			"aliases":         aliases,
+			"affected_ranges": AffectedRanges(v, pkg),
+			"cwe_ids":         v.DatabaseSpecific.CweIDs,
+			"max_severity":    group.MaxSeverity,
+		},
+	}
+}
+
+func groupFor(groups []GroupInfo, id string) GroupInfo {
+	for _, g := range groups {
+		for _, gid := range g.IDs {
+			if gid == id {
+				return g
+			}
+		}
+	}
+	return GroupInfo{IDs: []string{id}}
+}
+
+func mergeAliases(v Vulnerability, group GroupInfo) []string {
+	seen := map[string]bool{v.ID: true}
+	var aliases []string
+	for _, list := range [][]string{v.Aliases, group.IDs, group.Aliases} {
+		for _, a := range list {
+			if !seen[a] {
+				seen[a] = true
+				aliases = append(aliases, a)
+			}
+		}
+	}
+	return aliases
+}
+
+// severityFor prefers the advisory database label (GHSA style) and falls back
+// to the group's max CVSS score computed by osv-scanner.
+func severityFor(v Vulnerability, group GroupInfo) model.Severity {
+	if sev, err := model.ParseSeverity(v.DatabaseSpecific.Severity); err == nil {
+		return sev
+	}
+	if score, err := strconv.ParseFloat(group.MaxSeverity, 64); err == nil {
+		return SeverityFromScore(score)
+	}
+	return model.SeverityUnknown
+}
+
+// SeverityFromScore maps a CVSS base score to a severity band.
+func SeverityFromScore(score float64) model.Severity {
+	switch {
+	case score >= 9.0:
+		return model.SeverityCritical
+	case score >= 7.0:
+		return model.SeverityHigh
+	case score >= 4.0:
+		return model.SeverityMedium
+	case score > 0:
+		return model.SeverityLow
+	default:
+		return model.SeverityUnknown
+	}
+}
+
+func advisoryURL(v Vulnerability) string {
+	for _, r := range v.References {
+		if r.Type == "ADVISORY" {
+			return r.URL
+		}
+	}
+	if len(v.References) > 0 {
+		return v.References[0].URL
+	}
+	return ""
+}
+
+// FixedVersion returns the "fixed" event of the affected range that contains the installed
+// version, so a package in a later range is not reported as fixed in an older release.
+// When no range can be evaluated, the lowest fix above the installed version is used. A version
+// the ecosystem's ordering cannot parse gets no fix rather than one picked by a wrong order.
+func FixedVersion(v Vulnerability, pkg PackageInfo) string {
+	cmp, ok := compareForEcosystem(pkg.Ecosystem, pkg.Version)
+	if !ok {
+		return ""
+	}
+	fallback := ""
+	for _, a := range v.Affected {
+		if !matchesPackage(a.Package, pkg) {
+			continue
+		}
+		if affected, fixed := IsAffected(a, pkg.Version, cmp); affected {
+			return fixed
+		}
+		if fallback == "" {
+			fallback = nextFixed(a.Ranges, pkg.Version, cmp)
+		}
+	}
+	return fallback
+}
+
+// compareForEcosystem picks the version ordering of an OSV ecosystem and reports whether
+// version can be parsed by it. Ecosystems without their own scheme (npm, Go, crates.io, ...)
+// use SemVer.
+func compareForEcosystem(ecosystem, version string) (CompareFunc, bool) {
+	switch NormalizeEcosystem(ecosystem) {
+	case "nuget":
+		_, _, ok := splitVersion(version, 4)
+		return CompareNuGet, ok
+	case "pypi":
+		_, ok := parsePyPI(version)
+		return ComparePyPI, ok
+	case "maven":
+		return CompareMaven, len(segments(version)) > 0
+	case "rubygems", "packagist":
+		return CompareRubyGems, len(segments(version)) > 0
+	default:
+		_, _, ok := splitVersion(version, 3)
+		return CompareSemver, ok
+	}
+}
+
+// AffectedRanges renders the affected ranges for the package, e.g. "SEMVER >=0 <1.2.6".
+func AffectedRanges(v Vulnerability, pkg PackageInfo) []string {
+	var ranges []string
+	for _, a := range v.Affected {
+		if !matchesPackage(a.Package, pkg) {
+			continue
+		}
+		for _, r := range a.Ranges {
+			parts := []string{r.Type}
+			for _, e := range r.Events {
+				switch {
+				case e.Introduced != "":
+					parts = append(parts, ">="+e.Introduced)
+				case e.Fixed != "":
+					parts = append(parts, "<"+e.Fixed)
+				case e.LastAffected != "":
+					parts = append(parts, "<="+e.LastAffected)
+				case e.Limit != "":
+					parts = append(parts, "limit:"+e.Limit)
+				}
+			}
+			ranges = append(ranges, strings.Join(parts, " "))
+		}
+	}
+	return ranges
+}
+
+func matchesPackage(a PackageInfo, pkg PackageInfo) bool {
+	if a.Name == "" {
+		return true
+	}
+	return a.Name == pkg.Name && (a.Ecosystem == "" || strings.EqualFold(a.Ecosystem, pkg.Ecosystem))
+}
+
+// NormalizeEcosystem maps OSV ecosystem names (npm, NuGet, PyPI, Go, crates.io, Maven, ...)
+// to DepScanity's lower-case ecosystem labels.
+func NormalizeEcosystem(ecosystem string) string {
+	return strings.ToLower(ecosystem)
+}
@@ -0,0 +1,73 @@
+package osv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"depscanity/internal/model"
+)
+
+func TestParseOsvOutput(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "osv_output.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParseOsvOutput(string(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// minimist + one grouped finding for jinja2 (GHSA and PYSEC are the same issue)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+
+	f1 := findings[0]
+	if f1.Package != "minimist" || f1.Ecosystem != "npm" || f1.Source != "osv" {
+		t.Errorf("unexpected finding identity: %s/%s/%s", f1.Source, f1.Ecosystem, f1.Package)
+	}
+	if f1.Severity != model.SeverityCritical {
+		t.Errorf("expected Critical from database_specific, got %s", f1.Severity)
+	}
+	if f1.FixedVersion == nil || *f1.FixedVersion != "1.2.6" {
+		t.Errorf("expected the fix of the range containing 1.2.5 (1.2.6), got %v", f1.FixedVersion)
+	}
+	if f1.URL == nil || *f1.URL != "https://nvd.nist.gov/vuln/detail/CVE-2021-44906" {
+		t.Errorf("expected ADVISORY reference as URL, got %v", f1.URL)
+	}
+	if f1.Location != "/repo/frontend/package-lock.json" {
+		t.Errorf("expected lockfile location, got %s", f1.Location)
+	}
+	ranges, _ := f1.Metadata["affected_ranges"].([]string)
+	if len(ranges) != 2 || ranges[1] != "SEMVER >=1.0.0 <1.2.6" {
+		t.Errorf("unexpected affected ranges: %v", ranges)
+	}
+
+	f2 := findings[1]
+	if f2.VulnerabilityID != "GHSA-g3rq-g295-4j3m" || f2.Ecosystem != "pypi" {
+		t.Errorf("unexpected jinja2 finding: %s %s", f2.VulnerabilityID, f2.Ecosystem)
+	}
+	if f2.Severity != model.SeverityMedium {
+		t.Errorf("expected Medium from max_severity 5.3, got %s", f2.Severity)
+	}
+	aliases, _ := f2.Metadata["aliases"].([]string)
+	if len(aliases) != 2 || aliases[0] != "CVE-2020-28493" || aliases[1] != "PYSEC-2021-66" {
+		t.Errorf("unexpected aliases: %v", aliases)
+	}
+}
+
+func TestParseOsvOutput_Empty(t *testing.T) {
+	findings, err := ParseOsvOutput("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected 0 findings, got %d", len(findings))
+	}
+
+	if _, err := ParseOsvOutput("not json"); err == nil {
+		t.Error("expected error for invalid json")
+	}
+}
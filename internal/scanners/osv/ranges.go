@@ -0,0 +1,92 @@
+package osv
+
+import "sort"
+
+// IsAffected evaluates an OSV affected entry against an installed version.
+// It returns whether the version is affected and, when known, the version fixing the matching range.
+func IsAffected(a Affected, version string, cmp CompareFunc) (bool, string) {
+	for _, v := range a.Versions {
+		if cmp(v, version) == 0 {
+			return true, nextFixed(a.Ranges, version, cmp)
+		}
+	}
+
+	for _, r := range a.Ranges {
+		// GIT ranges refer to commits and cannot be evaluated against package versions
+		if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+			continue
+		}
+		if inRange(r, version, cmp) {
+			return true, fixedAfter(r, version, cmp)
+		}
+	}
+	return false, ""
+}
+
+// inRange walks the range events in version order as described by the OSV schema.
+func inRange(r Range, version string, cmp CompareFunc) bool {
+	events := sortedEvents(r.Events, cmp)
+
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" || cmp(version, e.Introduced) >= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if cmp(version, e.Fixed) >= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if cmp(version, e.LastAffected) > 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+func sortedEvents(events []Event, cmp CompareFunc) []Event {
+	sorted := append([]Event{}, events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		vi, vj := eventVersion(sorted[i]), eventVersion(sorted[j])
+		if vi == "0" || vj == "0" {
+			return vi == "0" && vj != "0"
+		}
+		return cmp(vi, vj) < 0
+	})
+	return sorted
+}
+
+func eventVersion(e Event) string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	case e.LastAffected != "":
+		return e.LastAffected
+	default:
+		return e.Limit
+	}
+}
+
+// fixedAfter returns the lowest fixed version in the range above the installed version.
+func fixedAfter(r Range, version string, cmp CompareFunc) string {
+	for _, e := range sortedEvents(r.Events, cmp) {
+		if e.Fixed != "" && cmp(e.Fixed, version) > 0 {
+			return e.Fixed
+		}
+	}
+	return ""
+}
+
+func nextFixed(ranges []Range, version string, cmp CompareFunc) string {
+	for _, r := range ranges {
+		if fixed := fixedAfter(r, version, cmp); fixed != "" {
+			return fixed
+		}
+	}
+	return ""
+}
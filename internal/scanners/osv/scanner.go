@@ -0,0 +1,123 @@
+package osv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+)
+
+// Scanner runs osv-scanner recursively on the repository root.
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the OSV scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "osv" }
+
+// Targets returns the repository root unless --no-osv is set.
+// OSV is optional: when osv-scanner is not installed the scanner is skipped with a warning.
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	if s.opts.NoOSV {
+		return nil
+	}
+	if _, err := exec.LookPath("osv-scanner"); err != nil {
+		fmt.Println("Warning: osv-scanner not found in PATH, skipping OSV scan (use --no-osv to silence).")
+		return nil
+	}
+	return []string{s.opts.Root}
+}
+
+func (s *Scanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	return ScanOsv(ctx, target, s.opts)
+}
+
+// ScanOsv executes osv-scanner -r <path> --json and parses the results.
+func ScanOsv(ctx context.Context, rootPath string, opts scanners.Options) ([]model.Finding, []report.ScannerError) {
+	var findings []model.Finding
+	var scannerErrors []report.ScannerError
+
+	// 1. Check osv-scanner existence
+	if _, err := exec.LookPath("osv-scanner"); err != nil {
+		scannerErrors = append(scannerErrors, report.ScannerError{
+			Source:   "osv",
+			Location: rootPath,
+			Message:  "osv-scanner executable not found in PATH",
+		})
+		return findings, scannerErrors
+	}
+
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
+	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
+		scannerErrors = append(scannerErrors, report.ScannerError{
+			Source:   "osv",
+			Location: rootPath,
+			Message:  fmt.Sprintf("failed to create raw output dir: %v", err),
+		})
+		return findings, scannerErrors
+	}
+
+	// 2. Run osv-scanner
+	// Exit code 1 means vulnerabilities were found, 128 means no packages were found.
+	args := []string{"-r", rootPath, "--json"}
+	res, err := opts.RunPhase(ctx, "osv", scanners.PhaseAudit, rootPath, "osv-scanner", args, rootPath)
+	if res.ExitCode == 124 {
+		scannerErrors = append(scannerErrors, report.ScannerError{
+			Source:   "osv",
+			Location: rootPath,
+			Message:  fmt.Sprintf("osv-scanner timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit)),
+		})
+		return findings, scannerErrors
+	}
+	if res.ExitCode == 127 {
+		scannerErrors = append(scannerErrors, report.ScannerError{
+			Source:   "osv",
+			Location: rootPath,
+			Message:  fmt.Sprintf("osv-scanner execution failed (code %d): %v\nStderr: %s", res.ExitCode, err, res.Stderr),
+		})
+		return findings, scannerErrors
+	}
+
+	// 3. Save raw JSON
+	sanitized := sanitizePath(rootPath)
+	rawFile := filepath.Join(rawOutDir, fmt.Sprintf("osv-%s.json", sanitized))
+	_ = os.WriteFile(rawFile, []byte(res.Stdout), 0644)
+
+	if res.ExitCode == 128 {
+		return findings, scannerErrors
+	}
+
+	// 4. Parse
+	findings, err = ParseOsvOutput(res.Stdout)
+	if err != nil {
+		scannerErrors = append(scannerErrors, report.ScannerError{
+			Source:   "osv",
+			Location: rootPath,
+			Message:  fmt.Sprintf("parse error: %v", err),
+		})
+	}
+
+	return findings, scannerErrors
+}
+
+func sanitizePath(path string) string {
+	s := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, path)
+	return strings.Trim(s, "_")
+}
@@ -0,0 +1,354 @@
+package osv
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CompareFunc compares two versions, returning -1, 0 or +1.
+type CompareFunc func(a, b string) int
+
+// CompareSemver compares npm versions following SemVer 2.0 precedence.
+// Build metadata is ignored; missing minor/patch parts count as zero.
+func CompareSemver(a, b string) int {
+	return compareVersions(a, b, 3, false)
+}
+
+// CompareNuGet compares NuGet versions: up to four numeric parts
+// (missing parts count as zero) and a case-insensitive prerelease label.
+func CompareNuGet(a, b string) int {
+	return compareVersions(a, b, 4, true)
+}
+
+func compareVersions(a, b string, parts int, foldCase bool) int {
+	na, pa, okA := splitVersion(a, parts)
+	nb, pb, okB := splitVersion(b, parts)
+	if !okA || !okB {
+		// Not a dotted numeric version (1.2.3.4, 1.0rc1): order segment by segment
+		return CompareRubyGems(a, b)
+	}
+
+	for i := 0; i < parts; i++ {
+		if na[i] != nb[i] {
+			if na[i] < nb[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	if foldCase {
+		pa = strings.ToLower(pa)
+		pb = strings.ToLower(pb)
+	}
+	return comparePrerelease(pa, pb)
+}
+
+// splitVersion parses "1.2.3-beta.1+build" into numeric parts and the prerelease label.
+func splitVersion(v string, parts int) ([]int, string, bool) {
+	v = strings.TrimSpace(v)
+	v = strings.TrimPrefix(v, "=")
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.Index(v, "+"); i >= 0 {
+		v = v[:i]
+	}
+
+	core, pre := v, ""
+	if i := strings.Index(v, "-"); i >= 0 {
+		core, pre = v[:i], v[i+1:]
+	}
+
+	fields := strings.Split(core, ".")
+	if core == "" || len(fields) > parts {
+		return nil, "", false
+	}
+
+	nums := make([]int, parts)
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, "", false
+		}
+		nums[i] = n
+	}
+	return nums, pre, true
+}
+
+// comparePrerelease orders prerelease labels: a release sorts after any prerelease,
+// numeric identifiers compare numerically and sort before alphanumeric ones.
+func comparePrerelease(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+
+	ia := strings.Split(a, ".")
+	ib := strings.Split(b, ".")
+	for i := 0; i < len(ia) && i < len(ib); i++ {
+		if ia[i] == ib[i] {
+			continue
+		}
+		xa, errA := strconv.Atoi(ia[i])
+		xb, errB := strconv.Atoi(ib[i])
+		switch {
+		case errA == nil && errB == nil:
+			if xa < xb {
+				return -1
+			}
+			return 1
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			return strings.Compare(ia[i], ib[i])
+		}
+	}
+
+	switch {
+	case len(ia) < len(ib):
+		return -1
+	case len(ia) > len(ib):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// CompareRubyGems compares versions like Gem::Version: segments split at dots, dashes and
+// digit/letter boundaries ("4.2.5.1", "1.0.0.rc1"), numbers numerically, letters lexically,
+// a letter segment before any number (so prereleases sort first) and missing segments as zero.
+func CompareRubyGems(a, b string) int {
+	sa, sb := segments(a), segments(b)
+	for i := 0; i < len(sa) || i < len(sb); i++ {
+		x, y := "0", "0"
+		if i < len(sa) {
+			x = sa[i]
+		}
+		if i < len(sb) {
+			y = sb[i]
+		}
+		if c := compareSegment(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// segments splits a version into its numeric and alphabetic runs, lower-cased.
+func segments(v string) []string {
+	v = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(v), "v"))
+	if i := strings.Index(v, "+"); i >= 0 {
+		v = v[:i]
+	}
+
+	var result []string
+	var current strings.Builder
+	digits := false
+	flush := func() {
+		if current.Len() > 0 {
+			result = append(result, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range v {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if current.Len() > 0 && unicode.IsDigit(r) != digits {
+			flush()
+		}
+		digits = unicode.IsDigit(r)
+		current.WriteRune(r)
+	}
+	flush()
+	return result
+}
+
+func compareSegment(x, y string) int {
+	nx, errX := strconv.Atoi(x)
+	ny, errY := strconv.Atoi(y)
+	switch {
+	case errX == nil && errY == nil:
+		return compareInts(nx, ny)
+	case errX == nil:
+		return 1
+	case errY == nil:
+		return -1
+	default:
+		return strings.Compare(x, y)
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// mavenQualifiers ranks the well-known Maven qualifiers; "" is the release itself.
+var mavenQualifiers = map[string]int{
+	"alpha": 0, "a": 0, "beta": 1, "b": 1, "milestone": 2, "m": 2, "rc": 3, "cr": 3,
+	"snapshot": 4, "": 5, "ga": 5, "final": 5, "release": 5, "sp": 6,
+}
+
+// CompareMaven compares Maven versions after ComparableVersion: numbers numerically,
+// known qualifiers in the order alpha < beta < milestone < rc < snapshot < release < sp,
+// unknown qualifiers after those, and missing segments as 0 or the release.
+func CompareMaven(a, b string) int {
+	sa, sb := segments(a), segments(b)
+	for i := 0; i < len(sa) || i < len(sb); i++ {
+		var x, y string
+		if i < len(sa) {
+			x = sa[i]
+		}
+		if i < len(sb) {
+			y = sb[i]
+		}
+		if c := compareMavenSegment(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareMavenSegment(x, y string) int {
+	nx, errX := strconv.Atoi(x)
+	ny, errY := strconv.Atoi(y)
+	// A missing segment is 0 next to a number and the release next to a qualifier
+	if x == "" && errY == nil {
+		nx, errX = 0, nil
+	}
+	if y == "" && errX == nil {
+		ny, errY = 0, nil
+	}
+	switch {
+	case errX == nil && errY == nil:
+		return compareInts(nx, ny)
+	case errX == nil:
+		return 1
+	case errY == nil:
+		return -1
+	}
+
+	rx, knownX := mavenQualifiers[x]
+	ry, knownY := mavenQualifiers[y]
+	switch {
+	case knownX && knownY:
+		return compareInts(rx, ry)
+	case knownX:
+		return -1
+	case knownY:
+		return 1
+	default:
+		return strings.Compare(x, y)
+	}
+}
+
+var pep440 = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d*))?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d*))?` +
+	`(?:[-_.]?(dev)[-_.]?(\d*))?(?:\+[a-z0-9._-]+)?$`)
+
+// pypiVersion is a parsed PEP 440 version. Absent pre, post and dev parts get values that
+// make a plain field-by-field comparison follow the PEP 440 order.
+type pypiVersion struct {
+	epoch   int
+	release []int
+	pre     [2]int
+	post    int
+	dev     int
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+func parsePyPI(v string) (pypiVersion, bool) {
+	m := pep440.FindStringSubmatch(strings.ToLower(strings.TrimSpace(v)))
+	if m == nil {
+		return pypiVersion{}, false
+	}
+	atoi := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+
+	p := pypiVersion{epoch: atoi(m[1]), post: -1, dev: maxInt}
+	for _, f := range strings.Split(m[2], ".") {
+		p.release = append(p.release, atoi(f))
+	}
+	switch m[3] {
+	case "":
+		p.pre = [2]int{maxInt, 0}
+	case "a", "alpha":
+		p.pre = [2]int{0, atoi(m[4])}
+	case "b", "beta":
+		p.pre = [2]int{1, atoi(m[4])}
+	default:
+		p.pre = [2]int{2, atoi(m[4])}
+	}
+	switch {
+	case m[5] != "":
+		p.post = atoi(m[5])
+	case m[6] != "":
+		p.post = atoi(m[7])
+	}
+	if m[8] != "" {
+		p.dev = atoi(m[9])
+		// A dev release of a final version (1.0.dev1) comes before its prereleases (1.0a1)
+		if m[3] == "" && p.post < 0 {
+			p.pre = [2]int{-1, 0}
+		}
+	}
+	return p, true
+}
+
+// ComparePyPI compares PEP 440 versions (epochs, any number of release parts, a/b/rc
+// prereleases, post and dev releases). Versions that are not PEP 440 are ordered
+// segment by segment like CompareRubyGems.
+func ComparePyPI(a, b string) int {
+	pa, okA := parsePyPI(a)
+	pb, okB := parsePyPI(b)
+	if !okA || !okB {
+		return CompareRubyGems(a, b)
+	}
+
+	if c := compareInts(pa.epoch, pb.epoch); c != 0 {
+		return c
+	}
+	for i := 0; i < len(pa.release) || i < len(pb.release); i++ {
+		x, y := 0, 0
+		if i < len(pa.release) {
+			x = pa.release[i]
+		}
+		if i < len(pb.release) {
+			y = pb.release[i]
+		}
+		if c := compareInts(x, y); c != 0 {
+			return c
+		}
+	}
+	for _, c := range []int{
+		compareInts(pa.pre[0], pb.pre[0]),
+		compareInts(pa.pre[1], pb.pre[1]),
+		compareInts(pa.post, pb.post),
+		compareInts(pa.dev, pb.dev),
+	} {
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
@@ -0,0 +1,167 @@
+package osv
+
+import "testing"
+
+func TestCompareSemver(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.10.0", -1},
+		{"v2.0.0", "1.9.9", 1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha.2", "1.0.0-alpha.10", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-1", "1.0.0-beta", -1},
+		{"1.0.0+build.1", "1.0.0", 0},
+		{"1.2", "1.2.0", 0},
+	}
+	for _, c := range cases {
+		if got := CompareSemver(c.a, c.b); got != c.want {
+			t.Errorf("CompareSemver(%s, %s) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestCompareNuGet(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"4.3.0", "4.3.0.0", 0},
+		{"4.3.0.1", "4.3.0", 1},
+		{"6.0.0-RC.1", "6.0.0-rc.1", 0},
+		{"6.0.0-preview.7", "6.0.0", -1},
+		{"13.0.1", "9.0.1", 1},
+	}
+	for _, c := range cases {
+		if got := CompareNuGet(c.a, c.b); got != c.want {
+			t.Errorf("CompareNuGet(%s, %s) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestComparePyPI(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.dev1", "1.0a1", -1},
+		{"1.0a1", "1.0rc1", -1},
+		{"1.0rc1", "1.0", -1},
+		{"1.0", "1.0.post1", -1},
+		{"1.0.post1", "1.0.post1.dev2", 1},
+		{"2.10.0.post1", "2.9", 1},
+		{"2.10.0.post1", "2.10.0", 1},
+		{"10.0", "9.0", 1},
+		{"1.0", "1.0.0", 0},
+		{"1!1.0", "2.0", 1},
+	}
+	for _, c := range cases {
+		if got := ComparePyPI(c.a, c.b); got != c.want {
+			t.Errorf("ComparePyPI(%s, %s) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestCompareMaven(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3.4", "1.2.3", 1},
+		{"1.0-rc1", "1.0", -1},
+		{"1.0-SNAPSHOT", "1.0", -1},
+		{"1.0-alpha1", "1.0-beta1", -1},
+		{"1.0.Final", "1.0", 0},
+		{"10.0", "9.0", 1},
+	}
+	for _, c := range cases {
+		if got := CompareMaven(c.a, c.b); got != c.want {
+			t.Errorf("CompareMaven(%s, %s) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestCompareRubyGems(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"4.2.5.1", "4.2.5", 1},
+		{"1.0.0.rc1", "1.0.0", -1},
+		{"10.0", "9.0", 1},
+		{"1.0", "1.0.0", 0},
+	}
+	for _, c := range cases {
+		if got := CompareRubyGems(c.a, c.b); got != c.want {
+			t.Errorf("CompareRubyGems(%s, %s) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestFixedVersion(t *testing.T) {
+	v := Vulnerability{Affected: []Affected{
+		{
+			Package: PackageInfo{Name: "django", Ecosystem: "PyPI"},
+			Ranges:  []Range{{Type: "ECOSYSTEM", Events: []Event{{Introduced: "0"}, {Fixed: "2.2.28"}}}},
+		},
+		{
+			Package: PackageInfo{Name: "rails", Ecosystem: "RubyGems"},
+			Ranges:  []Range{{Type: "ECOSYSTEM", Events: []Event{{Introduced: "4.2.0"}, {Fixed: "4.2.5.2"}}}},
+		},
+		{
+			Package: PackageInfo{Name: "lodash", Ecosystem: "npm"},
+			Ranges:  []Range{{Type: "SEMVER", Events: []Event{{Introduced: "0"}, {Fixed: "4.17.21"}}}},
+		},
+	}}
+
+	cases := []struct {
+		pkg  PackageInfo
+		want string
+	}{
+		{PackageInfo{Name: "django", Ecosystem: "PyPI", Version: "2.2rc1"}, "2.2.28"},
+		{PackageInfo{Name: "django", Ecosystem: "PyPI", Version: "2.10.0.post1"}, ""},
+		{PackageInfo{Name: "rails", Ecosystem: "RubyGems", Version: "4.2.5.1"}, "4.2.5.2"},
+		{PackageInfo{Name: "lodash", Ecosystem: "npm", Version: "4.17.20"}, "4.17.21"},
+		// Not a version the ordering understands: no fix rather than a lexical guess
+		{PackageInfo{Name: "lodash", Ecosystem: "npm", Version: "github:lodash/lodash"}, ""},
+		{PackageInfo{Name: "django", Ecosystem: "PyPI", Version: "latest"}, ""},
+	}
+	for _, c := range cases {
+		if got := FixedVersion(v, c.pkg); got != c.want {
+			t.Errorf("FixedVersion(%s %s) = %q, want %q", c.pkg.Name, c.pkg.Version, got, c.want)
+		}
+	}
+}
+
+func TestIsAffected(t *testing.T) {
+	a := Affected{
+		Ranges: []Range{
+			{Type: "SEMVER", Events: []Event{{Introduced: "1.0.0"}, {Fixed: "1.2.6"}}},
+			{Type: "SEMVER", Events: []Event{{Introduced: "2.0.0"}, {LastAffected: "2.1.0"}}},
+		},
+		Versions: []string{"0.9.0"},
+	}
+
+	cases := []struct {
+		version  string
+		affected bool
+		fixed    string
+	}{
+		{"0.5.0", false, ""},
+		{"0.9.0", true, "1.2.6"},
+		{"1.0.0", true, "1.2.6"},
+		{"1.2.5", true, "1.2.6"},
+		{"1.2.6", false, ""},
+		{"2.1.0", true, ""},
+		{"2.1.1", false, ""},
+	}
+	for _, c := range cases {
+		affected, fixed := IsAffected(a, c.version, CompareSemver)
+		if affected != c.affected || fixed != c.fixed {
+			t.Errorf("IsAffected(%s) = (%v, %q), want (%v, %q)", c.version, affected, fixed, c.affected, c.fixed)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package osvoffline
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"depscanity/internal/scanners/osv"
+)
+
+// Database indexes OSV advisories by ecosystem and package name.
+type Database struct {
+	byPackage map[string][]osv.Vulnerability
+	count     int
+	skipped   int
+}
+
+// Open loads an OSV export from a directory of advisory JSON files (optionally
+// containing per-ecosystem all.zip archives) or from a single zip archive.
+// Only advisories for the ecosystems Match serves (npm, NuGet) are kept; withdrawn
+// advisories and files that are not valid OSV JSON are skipped.
+func Open(path string) (*Database, error) {
+	db := &Database{byPackage: make(map[string][]osv.Vulnerability)}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open advisory database: %w", err)
+	}
+
+	if !info.IsDir() {
+		if err := db.loadZip(path); err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".json":
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			db.add(p, data)
+			return nil
+		case ".zip":
+			return db.loadZip(p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (db *Database) loadZip(path string) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("failed to open advisory archive %s: %w", path, err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || !strings.HasSuffix(strings.ToLower(f.Name), ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s in %s: %w", f.Name, path, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s in %s: %w", f.Name, path, err)
+		}
+		db.add(path+"!"+f.Name, data)
+	}
+	return nil
+}
+
+// add indexes an advisory under the packages it affects in the served ecosystems.
+// A file that is not valid OSV JSON is reported and skipped so the rest of the export still loads.
+func (db *Database) add(name string, data []byte) {
+	var v osv.Vulnerability
+	if err := json.Unmarshal(data, &v); err != nil {
+		fmt.Printf("  [osv-offline] Skipping %s: %v\n", name, err)
+		db.skipped++
+		return
+	}
+	if v.ID == "" || v.Withdrawn != "" {
+		return
+	}
+
+	indexed := make(map[string]bool)
+	for _, a := range v.Affected {
+		key := packageKey(a.Package.Ecosystem, a.Package.Name)
+		if a.Package.Name == "" || indexed[key] || compareFor(osv.NormalizeEcosystem(a.Package.Ecosystem)) == nil {
+			continue
+		}
+		indexed[key] = true
+		db.byPackage[key] = append(db.byPackage[key], v)
+	}
+	if len(indexed) > 0 {
+		db.count++
+	}
+}
+
+// Lookup returns the advisories affecting a package in the given ecosystem.
+func (db *Database) Lookup(ecosystem, name string) []osv.Vulnerability {
+	return db.byPackage[packageKey(ecosystem, name)]
+}
+
+// Len returns the number of advisories loaded.
+func (db *Database) Len() int {
+	return db.count
+}
+
+// Skipped returns the number of files that could not be parsed as OSV advisories.
+func (db *Database) Skipped() int {
+	return db.skipped
+}
+
+// packageKey normalizes ecosystem and name; npm and NuGet names are case-insensitive.
+func packageKey(ecosystem, name string) string {
+	return osv.NormalizeEcosystem(ecosystem) + "|" + strings.ToLower(name)
+}
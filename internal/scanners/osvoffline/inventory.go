@@ -0,0 +1,175 @@
+package osvoffline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Package is an installed package resolved from a lockfile.
+type Package struct {
+	Name      string
+	Version   string
+	Ecosystem string
+}
+
+// LoadInventory resolves installed packages from package-lock.json, bun.lock or project.assets.json.
+func LoadInventory(path string) ([]Package, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Base(path)) {
+	case "package-lock.json":
+		return parsePackageLock(content)
+	case "bun.lock":
+		return parseBunLock(content)
+	case "project.assets.json":
+		return parseAssetsFile(content)
+	default:
+		return nil, fmt.Errorf("unsupported lockfile: %s", filepath.Base(path))
+	}
+}
+
+// parsePackageLock collects every installed version from v1 "dependencies" or v2/v3 "packages".
+func parsePackageLock(content []byte) ([]Package, error) {
+	type dependency struct {
+		Version      string                     `json:"version"`
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	}
+	type PackageLock struct {
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+		Packages     map[string]struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			Link    bool   `json:"link"`
+		} `json:"packages"`
+	}
+
+	var lock PackageLock
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal package-lock.json: %w", err)
+	}
+
+	set := make(map[Package]bool)
+	if lock.Packages != nil {
+		for path, pkg := range lock.Packages {
+			if path == "" || pkg.Link || pkg.Version == "" {
+				continue
+			}
+			idx := strings.LastIndex(path, "node_modules/")
+			if idx < 0 {
+				// Workspace member, not an installed dependency
+				continue
+			}
+			name := path[idx+len("node_modules/"):]
+			if pkg.Name != "" {
+				name = pkg.Name
+			}
+			set[Package{Name: name, Version: pkg.Version, Ecosystem: "npm"}] = true
+		}
+	} else {
+		// V1: nested dependencies tree
+		var walk func(deps map[string]json.RawMessage)
+		walk = func(deps map[string]json.RawMessage) {
+			for name, raw := range deps {
+				var d dependency
+				if err := json.Unmarshal(raw, &d); err != nil {
+					continue
+				}
+				if d.Version != "" {
+					set[Package{Name: name, Version: d.Version, Ecosystem: "npm"}] = true
+				}
+				walk(d.Dependencies)
+			}
+		}
+		walk(lock.Dependencies)
+	}
+
+	return sortedPackages(set), nil
+}
+
+var trailingCommaRegex = regexp.MustCompile(`,\s*([}\]])`)
+
+// parseBunLock reads the "packages" section of bun.lock, whose values start with "name@version".
+func parseBunLock(content []byte) ([]Package, error) {
+	strContent := trailingCommaRegex.ReplaceAllString(string(content), "$1")
+
+	type BunLock struct {
+		Packages map[string][]any `json:"packages"`
+	}
+	var lock BunLock
+	if err := json.Unmarshal([]byte(strContent), &lock); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bun.lock: %w", err)
+	}
+
+	set := make(map[Package]bool)
+	for _, val := range lock.Packages {
+		if len(val) == 0 {
+			continue
+		}
+		spec, ok := val[0].(string)
+		if !ok {
+			continue
+		}
+		// Handle scoped packages: @scope/pkg@1.0.0
+		idx := strings.LastIndex(spec, "@")
+		if idx <= 0 {
+			continue
+		}
+		name, version := spec[:idx], spec[idx+1:]
+		if strings.Contains(version, ":") {
+			// workspace:, link:, file: and git references have no registry version
+			continue
+		}
+		set[Package{Name: name, Version: version, Ecosystem: "npm"}] = true
+	}
+
+	return sortedPackages(set), nil
+}
+
+// parseAssetsFile reads the "libraries" section of obj/project.assets.json ("Name/Version" keys).
+func parseAssetsFile(content []byte) ([]Package, error) {
+	type Assets struct {
+		Libraries map[string]struct {
+			Type string `json:"type"`
+		} `json:"libraries"`
+	}
+	var assets Assets
+	if err := json.Unmarshal(content, &assets); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal project.assets.json: %w", err)
+	}
+
+	set := make(map[Package]bool)
+	for key, lib := range assets.Libraries {
+		if lib.Type != "package" {
+			continue
+		}
+		name, version, ok := strings.Cut(key, "/")
+		if !ok {
+			continue
+		}
+		set[Package{Name: name, Version: version, Ecosystem: "nuget"}] = true
+	}
+
+	return sortedPackages(set), nil
+}
+
+func sortedPackages(set map[Package]bool) []Package {
+	result := make([]Package, 0, len(set))
+	for p := range set {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Version < result[j].Version
+	})
+	return result
+}
@@ -0,0 +1,90 @@
+package osvoffline
+
+import (
+	"depscanity/internal/model"
+	"depscanity/internal/scanners/osv"
+)
+
+// Match evaluates the inventory against the database and returns one finding per affected package/advisory.
+func Match(db *Database, pkgs []Package, location string) []model.Finding {
+	var findings []model.Finding
+
+	for _, pkg := range pkgs {
+		cmp := compareFor(pkg.Ecosystem)
+		if cmp == nil {
+			continue
+		}
+
+		for _, v := range db.Lookup(pkg.Ecosystem, pkg.Name) {
+			for _, a := range v.Affected {
+				if packageKey(a.Package.Ecosystem, a.Package.Name) != packageKey(pkg.Ecosystem, pkg.Name) {
+					continue
+				}
+				affected, fixed := osv.IsAffected(a, pkg.Version, cmp)
+				if !affected {
+					continue
+				}
+				findings = append(findings, newFinding(pkg, v, a, fixed, location))
+				break
+			}
+		}
+	}
+
+	return findings
+}
+
+func compareFor(ecosystem string) osv.CompareFunc {
+	switch ecosystem {
+	case "npm":
+		return osv.CompareSemver
+	case "nuget":
+		return osv.CompareNuGet
+	default:
+		return nil
+	}
+}
+
+func newFinding(pkg Package, v osv.Vulnerability, a osv.Affected, fixed string, location string) model.Finding {
+	// Only GHSA entries carry a severity label; the others are rated from their CVSS vector
+	sev, err := model.ParseSeverity(v.DatabaseSpecific.Severity)
+	if err != nil {
+		sev = osv.VectorSeverity(v)
+	}
+
+	title := v.Summary
+	if title == "" {
+		title = v.ID
+	}
+
+	var fixedPtr *string
+	if fixed != "" {
+		fixedPtr = &fixed
+	}
+
+	var urlPtr *string
+	for _, r := range v.References {
+		if r.Type == "ADVISORY" {
+			url := r.URL
+			urlPtr = &url
+			break
+		}
+	}
+
+	return model.Finding{
+		Source:           "osv-offline",
+		Ecosystem:        pkg.Ecosystem,
+		Package:          pkg.Name,
+		InstalledVersion: pkg.Version,
+		FixedVersion:     fixedPtr,
+		VulnerabilityID:  v.ID,
+		Severity:         sev,
+		Title:            &title,
+		URL:              urlPtr,
+		Location:         location,
+		Metadata: map[string]any{
+			"aliases":         v.Aliases,
+			"affected_ranges": osv.AffectedRanges(osv.Vulnerability{Affected: []osv.Affected{a}}, a.Package),
+			"cwe_ids":         v.DatabaseSpecific.CweIDs,
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package osvoffline
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"depscanity/internal/model"
+)
+
+func TestMatch_Directory(t *testing.T) {
+	db, err := Open(filepath.Join("testdata", "db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if db.Len() != 2 {
+		t.Errorf("expected 2 advisories (withdrawn skipped), got %d", db.Len())
+	}
+
+	lock := filepath.Join("testdata", "package-lock.json")
+	pkgs, err := LoadInventory(lock)
+	if err != nil {
+		t.Fatalf("LoadInventory failed: %v", err)
+	}
+	if len(pkgs) != 3 {
+		t.Fatalf("expected 3 packages, got %d", len(pkgs))
+	}
+
+	findings := Match(db, pkgs, lock)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings (both minimist versions), got %d", len(findings))
+	}
+
+	fixes := map[string]string{}
+	for _, f := range findings {
+		if f.Source != "osv-offline" || f.Ecosystem != "npm" || f.Severity != model.SeverityCritical {
+			t.Errorf("unexpected finding: %+v", f)
+		}
+		if f.FixedVersion != nil {
+			fixes[f.InstalledVersion] = *f.FixedVersion
+		}
+	}
+	if fixes["0.0.8"] != "0.2.4" || fixes["1.2.5"] != "1.2.6" {
+		t.Errorf("expected fix per matching range, got %v", fixes)
+	}
+}
+
+func TestMatch_ZipAndNuGet(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "all.zip")
+	out, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(out)
+	data, err := os.ReadFile(filepath.Join("testdata", "db", "nuget", "GHSA-5crp-9r3c-p9vr.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, _ := zw.Create("GHSA-5crp-9r3c-p9vr.json")
+	w.Write(data)
+	zw.Close()
+	out.Close()
+
+	db, err := Open(zipPath)
+	if err != nil {
+		t.Fatalf("Open zip failed: %v", err)
+	}
+
+	assets := filepath.Join("testdata", "project.assets.json")
+	pkgs, err := LoadInventory(assets)
+	if err != nil {
+		t.Fatalf("LoadInventory failed: %v", err)
+	}
+	if len(pkgs) != 2 {
+		t.Errorf("expected 2 NuGet packages (project reference skipped), got %d", len(pkgs))
+	}
+
+	findings := Match(db, pkgs, assets)
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d", len(findings))
+	}
+	f := findings[0]
+	if f.Package != "Newtonsoft.Json" || f.Ecosystem != "nuget" || f.Severity != model.SeverityHigh {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+	if f.FixedVersion == nil || *f.FixedVersion != "13.0.1" {
+		t.Errorf("expected fixed version 13.0.1, got %v", f.FixedVersion)
+	}
+}
+
+func TestOpen_SkipsUnparsableAndForeignAdvisories(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("broken.json", `{"id": "GHSA-broken",`)
+	write("PYSEC-2021-1.json", `{"id": "PYSEC-2021-1", "affected": [{"package": {"ecosystem": "PyPI", "name": "django"}}]}`)
+	// No database_specific severity: rated from the CVSS vector
+	write("GHSA-vector.json", `{
+		"id": "GHSA-vector",
+		"severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}],
+		"affected": [{"package": {"ecosystem": "npm", "name": "lodash"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]}]
+	}`)
+
+	db, err := Open(dir)
+	if err != nil {
+		t.Fatalf("expected a malformed file not to abort loading, got %v", err)
+	}
+	if db.Len() != 1 || db.Skipped() != 1 {
+		t.Errorf("expected 1 npm advisory and 1 skipped file, got %d / %d", db.Len(), db.Skipped())
+	}
+	if len(db.Lookup("PyPI", "django")) != 0 {
+		t.Error("expected advisories of unserved ecosystems not to be indexed")
+	}
+
+	findings := Match(db, []Package{{Name: "lodash", Version: "4.17.20", Ecosystem: "npm"}}, "package-lock.json")
+	if len(findings) != 1 || findings[0].Severity != model.SeverityCritical {
+		t.Errorf("expected a critical finding from the CVSS vector, got %+v", findings)
+	}
+}
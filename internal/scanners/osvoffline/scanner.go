@@ -0,0 +1,86 @@
+package osvoffline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+)
+
+// Scanner matches lockfile inventories against a local OSV database dump without network access.
+type Scanner struct {
+	opts scanners.Options
+
+	once  sync.Once
+	db    *Database
+	dbErr error
+}
+
+// New returns the offline OSV matcher.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "osv-offline" }
+
+// Targets returns npm and bun lockfiles plus obj/project.assets.json of detected .NET projects.
+// The scanner only runs when --advisory-db is set.
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	if s.opts.AdvisoryDB == "" {
+		return nil
+	}
+
+	var targets []string
+	targets = append(targets, det.Npm...)
+	targets = append(targets, det.Bun...)
+	for _, proj := range det.Dotnet {
+		if detect.IsDotnetSolution(proj) {
+			continue
+		}
+		assets := filepath.Join(filepath.Dir(proj), "obj", "project.assets.json")
+		if _, err := os.Stat(assets); err == nil {
+			targets = append(targets, assets)
+		}
+	}
+	return targets
+}
+
+// Scan matches one target. The database is loaded by the first target; when it cannot be
+// opened, that target reports the error and the others are skipped silently.
+func (s *Scanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	opened := false
+	s.once.Do(func() {
+		opened = true
+		s.db, s.dbErr = Open(s.opts.AdvisoryDB)
+		if s.dbErr == nil {
+			fmt.Printf("  [osv-offline] Loaded %d advisories from %s (%d files skipped)\n", s.db.Len(), s.opts.AdvisoryDB, s.db.Skipped())
+		}
+	})
+	if s.dbErr != nil {
+		if !opened {
+			return nil, nil
+		}
+		return nil, []report.ScannerError{{
+			Source:   "osv-offline",
+			Location: s.opts.AdvisoryDB,
+			Message:  s.dbErr.Error(),
+		}}
+	}
+
+	pkgs, err := LoadInventory(target)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "osv-offline",
+			Location: target,
+			Message:  fmt.Sprintf("inventory error: %v", err),
+		}}
+	}
+
+	return Match(s.db, pkgs, target), nil
+}
@@ -0,0 +1,123 @@
+package pipaudit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"depscanity/internal/model"
+)
+
+// Dependency is one audited distribution in pip-audit's JSON output.
+type Dependency struct {
+	Name       string          `json:"name"`
+	Version    string          `json:"version"`
+	Vulns      []Vulnerability `json:"vulns"`
+	SkipReason string          `json:"skip_reason"`
+}
+
+// Vulnerability is a single advisory reported by pip-audit.
+type Vulnerability struct {
+	ID          string   `json:"id"`
+	FixVersions []string `json:"fix_versions"`
+	Aliases     []string `json:"aliases"`
+	Description string   `json:"description"`
+}
+
+// Output is the pip-audit 2.x JSON document (`pip-audit --format json`).
+type Output struct {
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+// maxTitleLen bounds the title derived from the advisory description.
+const maxTitleLen = 120
+
+// ParsePipAuditOutput parses `pip-audit --format json` output.
+// Both the 2.x document and the bare dependency list of older releases are accepted.
+func ParsePipAuditOutput(output string, manifestPath string) ([]model.Finding, error) {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil, nil
+	}
+
+	var deps []Dependency
+	if strings.HasPrefix(output, "[") {
+		if err := json.Unmarshal([]byte(output), &deps); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal pip-audit json: %w", err)
+		}
+	} else {
+		var doc Output
+		if err := json.Unmarshal([]byte(output), &doc); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal pip-audit json: %w", err)
+		}
+		deps = doc.Dependencies
+	}
+
+	var findings []model.Finding
+	for _, dep := range deps {
+		for _, v := range dep.Vulns {
+			f := model.Finding{
+				Source:           "pip-audit",
+				Ecosystem:        "pypi",
+				Package:          dep.Name,
+				InstalledVersion: dep.Version,
+				VulnerabilityID:  v.ID,
+				// pip-audit does not report severities
+				Severity: model.SeverityUnknown,
+				Location: manifestPath,
+				Metadata: map[string]any{},
+			}
+
+			if len(v.FixVersions) > 0 {
+				fixed := v.FixVersions[0]
+				f.FixedVersion = &fixed
+				f.Metadata["fix_versions"] = v.FixVersions
+			}
+			if title := titleFromDescription(v.Description); title != "" {
+				f.Title = &title
+			}
+			if v.Description != "" {
+				f.Metadata["description"] = v.Description
+			}
+			if len(v.Aliases) > 0 {
+				aliases := append([]string(nil), v.Aliases...)
+				sort.Strings(aliases)
+				f.Metadata["aliases"] = aliases
+			}
+			if url := advisoryURL(v.ID); url != "" {
+				f.URL = &url
+			}
+
+			findings = append(findings, f)
+		}
+	}
+
+	return findings, nil
+}
+
+// titleFromDescription returns the first line of the description, truncated to maxTitleLen.
+func titleFromDescription(desc string) string {
+	title := strings.TrimSpace(desc)
+	if i := strings.IndexByte(title, '\n'); i >= 0 {
+		title = strings.TrimSpace(title[:i])
+	}
+	if len(title) > maxTitleLen {
+		title = strings.TrimSpace(title[:maxTitleLen]) + "..."
+	}
+	return title
+}
+
+// advisoryURL links PYSEC and GHSA identifiers to their public advisory pages.
+func advisoryURL(id string) string {
+	switch {
+	case strings.HasPrefix(id, "GHSA-"):
+		return "https://github.com/advisories/" + id
+	case strings.HasPrefix(id, "PYSEC-"), strings.HasPrefix(id, "OSV-"):
+		return "https://osv.dev/vulnerability/" + id
+	case strings.HasPrefix(id, "CVE-"):
+		return "https://nvd.nist.gov/vuln/detail/" + id
+	default:
+		return ""
+	}
+}
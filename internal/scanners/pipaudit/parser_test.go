@@ -0,0 +1,78 @@
+package pipaudit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePipAuditOutput(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "pip_audit_output.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParsePipAuditOutput(string(data), "/repo/requirements.txt")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %d", len(findings))
+	}
+
+	jinja := findings[0]
+	if jinja.Source != "pip-audit" || jinja.Ecosystem != "pypi" || jinja.Package != "jinja2" || jinja.InstalledVersion != "2.11.2" {
+		t.Errorf("unexpected identity: %s/%s %s %s", jinja.Source, jinja.Ecosystem, jinja.Package, jinja.InstalledVersion)
+	}
+	if jinja.FixedVersion == nil || *jinja.FixedVersion != "2.11.3" {
+		t.Errorf("expected fixed version 2.11.3, got %v", jinja.FixedVersion)
+	}
+	aliases, _ := jinja.Metadata["aliases"].([]string)
+	if len(aliases) != 2 || aliases[0] != "CVE-2020-28493" {
+		t.Errorf("expected sorted aliases, got %v", aliases)
+	}
+	if jinja.Title == nil || len(*jinja.Title) > maxTitleLen+3 {
+		t.Errorf("expected truncated title, got %v", jinja.Title)
+	}
+	if jinja.URL == nil || *jinja.URL != "https://osv.dev/vulnerability/PYSEC-2021-66" {
+		t.Errorf("unexpected URL: %v", jinja.URL)
+	}
+
+	noFix := findings[2]
+	if noFix.FixedVersion != nil || noFix.Title != nil {
+		t.Errorf("expected no fix and no title, got %v %v", noFix.FixedVersion, noFix.Title)
+	}
+	if noFix.URL == nil || *noFix.URL != "https://github.com/advisories/GHSA-9wx4-h78v-vm56" {
+		t.Errorf("unexpected URL: %v", noFix.URL)
+	}
+}
+
+func TestParsePipAuditLegacyList(t *testing.T) {
+	output := `[{"name": "urllib3", "version": "1.26.4", "vulns": [{"id": "PYSEC-2021-108", "fix_versions": ["1.26.5"]}]}]`
+
+	findings, err := ParsePipAuditOutput(output, "/repo/requirements.txt")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Package != "urllib3" {
+		t.Fatalf("unexpected findings: %+v", findings)
+	}
+}
+
+func TestPipfileLockRequirements(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "Pipfile.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reqs, err := PipfileLockRequirements(data)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	expected := "certifi==2020.12.5\npytest==7.1.0\nrequests==2.25.0\n"
+	if reqs != expected {
+		t.Errorf("unexpected requirements:\n%s", reqs)
+	}
+}
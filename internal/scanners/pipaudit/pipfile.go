@@ -0,0 +1,51 @@
+package pipaudit
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// pipfileLock is the subset of Pipfile.lock needed to export pinned requirements.
+type pipfileLock struct {
+	Default map[string]pipfileEntry `json:"default"`
+	Develop map[string]pipfileEntry `json:"develop"`
+}
+
+type pipfileEntry struct {
+	Version string `json:"version"`
+}
+
+// PipfileLockRequirements converts the default and develop sections of a Pipfile.lock
+// into a pinned requirements file. Entries without a version (VCS, path) are skipped.
+func PipfileLockRequirements(data []byte) (string, error) {
+	var lock pipfileLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return "", err
+	}
+
+	pins := make(map[string]string)
+	for _, section := range []map[string]pipfileEntry{lock.Default, lock.Develop} {
+		for name, entry := range section {
+			version := strings.TrimPrefix(entry.Version, "==")
+			if version == "" || version == entry.Version {
+				continue
+			}
+			if _, ok := pins[name]; !ok {
+				pins[name] = version
+			}
+		}
+	}
+
+	names := make([]string, 0, len(pins))
+	for name := range pins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(name + "==" + pins[name] + "\n")
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,173 @@
+package pipaudit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+)
+
+// MaxManifests caps how many Python requirement files and lockfiles are audited per run.
+const MaxManifests = 10
+
+// Scanner audits requirements files and Poetry, Pipenv and uv lockfiles with pip-audit.
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the pip-audit scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "pip-audit" }
+
+func (s *Scanner) MaxTargets() int { return MaxManifests }
+
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	return det.Python
+}
+
+func (s *Scanner) Scan(ctx context.Context, manifestPath string) ([]model.Finding, []report.ScannerError) {
+	findings, err := ScanPipAudit(ctx, manifestPath, s.opts)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "pip-audit",
+			Location: manifestPath,
+			Message:  err.Error(),
+		}}
+	}
+	return findings, nil
+}
+
+// ScanPipAudit runs pip-audit against a requirements file, or against the pinned
+// requirements exported from a poetry.lock, uv.lock or Pipfile.lock, and parses the results.
+func ScanPipAudit(ctx context.Context, manifestPath string, opts scanners.Options) ([]model.Finding, error) {
+	// 1. Setup paths
+	workDir := filepath.Dir(manifestPath)
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
+	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create raw output dir: %w", err)
+	}
+	// Several manifests can live in one directory (requirements.txt, requirements-dev.txt),
+	// so raw files are keyed by the manifest path rather than the directory.
+	sanitizedName := sanitizePath(manifestPath)
+
+	// 2. Check pip-audit existence
+	if _, err := exec.LookPath("pip-audit"); err != nil {
+		return nil, fmt.Errorf("pip-audit executable not found in PATH")
+	}
+
+	// 3. Execution
+	// Lockfiles are exported to a fully pinned requirements file first (restore phase);
+	// pip-audit then audits it without re-resolving dependencies.
+	args := []string{"--format", "json", "--progress-spinner", "off"}
+	if strings.HasSuffix(strings.ToLower(manifestPath), ".txt") {
+		args = append(args, "-r", manifestPath)
+	} else {
+		// pip-audit runs in the manifest directory, so the exported file needs an absolute path
+		reqFile, err := filepath.Abs(filepath.Join(rawOutDir, fmt.Sprintf("pip-audit-%s.requirements.txt", sanitizedName)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve requirements path: %w", err)
+		}
+		if err := exportRequirements(ctx, manifestPath, reqFile, opts); err != nil {
+			return nil, err
+		}
+		args = append(args, "-r", reqFile, "--no-deps", "--disable-pip")
+	}
+
+	res, err := opts.RunPhase(ctx, "pip-audit", scanners.PhaseAudit, manifestPath, "pip-audit", args, workDir)
+	// pip-audit exits 1 when vulnerabilities are found
+	if res.ExitCode == 124 {
+		return nil, fmt.Errorf("pip-audit timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit))
+	}
+	if res.ExitCode == 127 {
+		return nil, fmt.Errorf("pip-audit failed execution (code %d): %v", res.ExitCode, err)
+	}
+
+	// 4. Save raw output
+	rawFile := filepath.Join(rawOutDir, fmt.Sprintf("pip-audit-%s.json", sanitizedName))
+	if err := os.WriteFile(rawFile, []byte(res.Stdout), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write raw output: %w", err)
+	}
+
+	if res.ExitCode != 0 && strings.TrimSpace(res.Stdout) == "" {
+		return nil, fmt.Errorf("pip-audit failed (code %d): %v\nStderr: %s", res.ExitCode, err, res.Stderr)
+	}
+
+	// 5. Parse
+	findings, err := ParsePipAuditOutput(res.Stdout, manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	return findings, nil
+}
+
+// exportRequirements writes the pinned dependency set of a lockfile to reqFile.
+// Poetry and uv export through their own CLI; Pipfile.lock is converted natively.
+func exportRequirements(ctx context.Context, lockPath, reqFile string, opts scanners.Options) error {
+	workDir := filepath.Dir(lockPath)
+
+	var tool string
+	var args []string
+	switch strings.ToLower(filepath.Base(lockPath)) {
+	case "pipfile.lock":
+		data, err := os.ReadFile(lockPath)
+		if err != nil {
+			return fmt.Errorf("failed to read Pipfile.lock: %w", err)
+		}
+		reqs, err := PipfileLockRequirements(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse Pipfile.lock: %w", err)
+		}
+		if err := os.WriteFile(reqFile, []byte(reqs), 0644); err != nil {
+			return fmt.Errorf("failed to write exported requirements: %w", err)
+		}
+		return nil
+	case "poetry.lock":
+		// Poetry 2 needs the poetry-plugin-export plugin for this command
+		tool = "poetry"
+		args = []string{"export", "--format", "requirements.txt", "--without-hashes"}
+	case "uv.lock":
+		tool = "uv"
+		args = []string{"export", "--format", "requirements-txt", "--no-hashes", "--frozen"}
+	default:
+		return fmt.Errorf("unsupported Python manifest: %s", filepath.Base(lockPath))
+	}
+
+	if _, err := exec.LookPath(tool); err != nil {
+		return fmt.Errorf("%s executable not found in PATH (needed to export %s)", tool, filepath.Base(lockPath))
+	}
+
+	res, err := opts.RunPhase(ctx, "pip-audit", scanners.PhaseRestore, lockPath, tool, args, workDir)
+	if res.ExitCode == 124 {
+		return fmt.Errorf("%s export timed out after %s (%s)", tool, res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseRestore))
+	}
+	if res.ExitCode != 0 {
+		return fmt.Errorf("%s export failed (code %d): %v\nStderr: %s", tool, res.ExitCode, err, res.Stderr)
+	}
+
+	if err := os.WriteFile(reqFile, []byte(res.Stdout), 0644); err != nil {
+		return fmt.Errorf("failed to write exported requirements: %w", err)
+	}
+	return nil
+}
+
+func sanitizePath(path string) string {
+	s := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, path)
+	return strings.Trim(s, "_")
+}
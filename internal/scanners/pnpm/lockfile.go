@@ -0,0 +1,187 @@
+package pnpm
+
+import (
+	"bufio"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lockfile holds the installed versions resolved from pnpm-lock.yaml.
+type Lockfile struct {
+	Version string
+	// Packages maps package names to every installed version.
+	Packages map[string][]string
+	// Importers maps workspace importers ("." for the root) to their direct dependencies and versions.
+	Importers map[string]map[string]string
+}
+
+// parsePnpmLock reads pnpm-lock.yaml without a YAML library, relying on pnpm's fixed two-space layout.
+// Package keys are "/name/1.0.0" (v5), "/name@1.0.0(peer@2.0.0)" (v6) or "name@1.0.0" (v9).
+func parsePnpmLock(lockPath string) (*Lockfile, error) {
+	file, err := os.Open(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	lock := &Lockfile{
+		Packages:  make(map[string][]string),
+		Importers: make(map[string]map[string]string),
+	}
+	versions := make(map[string]map[string]bool)
+	addVersion := func(name, version string) {
+		if name == "" || version == "" {
+			return
+		}
+		if versions[name] == nil {
+			versions[name] = make(map[string]bool)
+		}
+		versions[name][version] = true
+	}
+
+	var section, importer, group, dep string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		key, value, _ := strings.Cut(trimmed, ":")
+		key = unquote(key)
+		value = unquote(strings.TrimSpace(value))
+
+		switch indent {
+		case 0:
+			section = key
+			importer, group, dep = "", "", ""
+			switch section {
+			case "lockfileVersion":
+				lock.Version = value
+			case "dependencies", "devDependencies", "optionalDependencies":
+				// Single-project lockfiles (v5) list the root importer at top level
+				importer, group = ".", section
+			}
+		case 2:
+			switch section {
+			case "packages", "snapshots":
+				name, version := splitPackageKey(packageKeyFrom(trimmed), isLegacyLockfile(lock.Version))
+				addVersion(name, version)
+			case "importers":
+				importer = key
+			case "dependencies", "devDependencies", "optionalDependencies":
+				recordImporterDep(lock, ".", key, value)
+			}
+		case 4:
+			if section == "importers" {
+				group = key
+			}
+		case 6:
+			if section == "importers" && isDependencyGroup(group) {
+				dep = key
+				// v5 importers: "name: version" on one line
+				recordImporterDep(lock, importer, dep, value)
+			}
+		case 8:
+			if section == "importers" && isDependencyGroup(group) && key == "version" {
+				recordImporterDep(lock, importer, dep, value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, deps := range lock.Importers {
+		for name, version := range deps {
+			addVersion(name, version)
+		}
+	}
+	for name, set := range versions {
+		for v := range set {
+			lock.Packages[name] = append(lock.Packages[name], v)
+		}
+		sort.Strings(lock.Packages[name])
+	}
+	return lock, nil
+}
+
+func isDependencyGroup(group string) bool {
+	return group == "dependencies" || group == "devDependencies" || group == "optionalDependencies"
+}
+
+func recordImporterDep(lock *Lockfile, importer, name, version string) {
+	version = stripPeerSuffix(version)
+	if importer == "" || name == "" || version == "" || strings.Contains(version, ":") {
+		// link:, file: and workspace: references are not registry installs
+		return
+	}
+	if lock.Importers[importer] == nil {
+		lock.Importers[importer] = make(map[string]string)
+	}
+	lock.Importers[importer][name] = version
+}
+
+// isLegacyLockfile reports whether the lockfile predates v6 ("/name/version" package keys).
+func isLegacyLockfile(version string) bool {
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	return err == nil && n < 6
+}
+
+// packageKeyFrom extracts the mapping key from a "packages:" entry line such as
+// "/lodash@4.17.21:", "lodash@4.17.21: {}" or "'@babel/core@7.0.0(supports-color@5.5.0)':".
+func packageKeyFrom(trimmed string) string {
+	if strings.HasPrefix(trimmed, "'") || strings.HasPrefix(trimmed, "\"") {
+		if end := strings.IndexByte(trimmed[1:], trimmed[0]); end >= 0 {
+			return trimmed[1 : end+1]
+		}
+	}
+	if idx := strings.Index(trimmed, ": "); idx >= 0 {
+		return trimmed[:idx]
+	}
+	return strings.TrimSuffix(trimmed, ":")
+}
+
+// splitPackageKey splits a package key into name and version, dropping peer suffixes.
+func splitPackageKey(key string, legacy bool) (string, string) {
+	key = stripPeerSuffix(strings.TrimPrefix(key, "/"))
+	if key == "" {
+		return "", ""
+	}
+
+	// v6/v9: name@version, the separator is the first '@' after an optional scope
+	if !legacy {
+		if idx := strings.Index(key[1:], "@"); idx >= 0 {
+			return key[:idx+1], key[idx+2:]
+		}
+		return "", ""
+	}
+
+	// v5: name/version (scoped: @scope/name/version) with "_peer@1.0.0" suffixes
+	idx := strings.LastIndex(key, "/")
+	if idx <= 0 {
+		return "", ""
+	}
+	version := key[idx+1:]
+	if u := strings.Index(version, "_"); u >= 0 {
+		version = version[:u]
+	}
+	return key[:idx], version
+}
+
+// stripPeerSuffix removes "(peer@1.0.0)" suffixes used since lockfile v6.
+func stripPeerSuffix(v string) string {
+	if idx := strings.Index(v, "("); idx >= 0 {
+		return v[:idx]
+	}
+	return v
+}
+
+func unquote(s string) string {
+	return strings.Trim(strings.TrimSpace(s), "'\"")
+}
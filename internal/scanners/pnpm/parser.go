@@ -0,0 +1,194 @@
+package pnpm
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"depscanity/internal/model"
+)
+
+// AuditReport represents `pnpm audit --json` output (npm v6 audit format).
+type AuditReport struct {
+	Actions    []AuditAction       `json:"actions"`
+	Advisories map[string]Advisory `json:"advisories"`
+}
+
+// AuditAction is a suggested remediation resolving one or more advisories.
+type AuditAction struct {
+	Action   string `json:"action"`
+	Module   string `json:"module"`
+	Target   string `json:"target"`
+	Resolves []struct {
+		ID   int    `json:"id"`
+		Path string `json:"path"`
+	} `json:"resolves"`
+}
+
+type Advisory struct {
+	ID                 int      `json:"id"`
+	ModuleName         string   `json:"module_name"`
+	Title              string   `json:"title"`
+	URL                string   `json:"url"`
+	Severity           string   `json:"severity"`
+	VulnerableVersions string   `json:"vulnerable_versions"`
+	PatchedVersions    string   `json:"patched_versions"`
+	GithubAdvisoryID   string   `json:"github_advisory_id"`
+	CVEs               []string `json:"cves"`
+	Findings           []struct {
+		Version string   `json:"version"`
+		Paths   []string `json:"paths"`
+	} `json:"findings"`
+}
+
+var (
+	ghsaRegex    = regexp.MustCompile(`GHSA-[0-9a-z]{4}-[0-9a-z]{4}-[0-9a-z]{4}`)
+	patchedRegex = regexp.MustCompile(`^>=\s*([0-9][0-9A-Za-z.\-+]*)$`)
+)
+
+// ParsePnpmAudit parses JSON output from `pnpm audit --json` and attributes findings to workspace importers.
+func ParsePnpmAudit(jsonOutput string, lockPath string) ([]model.Finding, error) {
+	var findings []model.Finding
+	if strings.TrimSpace(jsonOutput) == "" {
+		return findings, nil
+	}
+
+	var report AuditReport
+	if err := json.Unmarshal([]byte(jsonOutput), &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pnpm audit json: %w", err)
+	}
+
+	lock, err := parsePnpmLock(lockPath)
+	if err != nil {
+		fmt.Printf("Warning: failed to parse pnpm-lock.yaml: %v\n", err)
+		lock = &Lockfile{}
+	}
+
+	// Fix targets suggested by "actions", keyed by advisory ID and the module the action changes.
+	// An "install" action names the top-level package, whose target is not a version of a
+	// transitive vulnerable module, so targets only count for the advisory's own module.
+	type fixKey struct {
+		id     int
+		module string
+	}
+	fixes := make(map[fixKey]string)
+	for _, a := range report.Actions {
+		if a.Target == "" {
+			continue
+		}
+		for _, r := range a.Resolves {
+			key := fixKey{r.ID, a.Module}
+			if _, exists := fixes[key]; !exists {
+				fixes[key] = a.Target
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(report.Advisories))
+	for id := range report.Advisories {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		adv := report.Advisories[id]
+		sev, err := model.ParseSeverity(adv.Severity)
+		if err != nil {
+			sev = model.SeverityUnknown
+		}
+		vulnID := vulnerabilityID(adv)
+
+		var fixed *string
+		if target, ok := fixes[fixKey{adv.ID, adv.ModuleName}]; ok {
+			fixed = &target
+		} else if m := patchedRegex.FindStringSubmatch(strings.TrimSpace(adv.PatchedVersions)); m != nil {
+			fixed = &m[1]
+		}
+
+		// Group audit paths by installed version
+		paths := make(map[string][]string)
+		for _, f := range adv.Findings {
+			paths[f.Version] = append(paths[f.Version], f.Paths...)
+		}
+		if len(paths) == 0 {
+			for _, v := range lock.Packages[adv.ModuleName] {
+				paths[v] = nil
+			}
+		}
+		if len(paths) == 0 {
+			paths["unknown"] = nil
+		}
+
+		versions := make([]string, 0, len(paths))
+		for v := range paths {
+			versions = append(versions, v)
+		}
+		sort.Strings(versions)
+
+		for _, version := range versions {
+			title := adv.Title
+			url := adv.URL
+			findings = append(findings, model.Finding{
+				Source:           "pnpm",
+				Ecosystem:        "npm",
+				Package:          adv.ModuleName,
+				InstalledVersion: version,
+				FixedVersion:     fixed,
+				VulnerabilityID:  vulnID,
+				Severity:         sev,
+				Title:            &title,
+				URL:              &url,
+				Location:         lockPath,
+				Metadata: map[string]any{
+					"vulnerable_versions": adv.VulnerableVersions,
+					"cves":                adv.CVEs,
+					"paths":               paths[version],
+					"importers":           importersFor(lock, adv.ModuleName, version, paths[version]),
+				},
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// importersFor returns the workspace importers that pull in the package.
+// pnpm audit paths start with the importer ("packages__api>express>qs" or ". > qs");
+// without paths, importers depending directly on the package are used.
+func importersFor(lock *Lockfile, pkgName, version string, paths []string) []string {
+	set := make(map[string]bool)
+	for _, p := range paths {
+		first := strings.TrimSpace(strings.Split(p, ">")[0])
+		if first == "" {
+			continue
+		}
+		// pnpm encodes "/" in importer paths as "__"
+		set[strings.ReplaceAll(first, "__", "/")] = true
+	}
+	if len(set) == 0 {
+		for importer, deps := range lock.Importers {
+			if v, ok := deps[pkgName]; ok && (v == version || version == "unknown") {
+				set[importer] = true
+			}
+		}
+	}
+
+	importers := make([]string, 0, len(set))
+	for i := range set {
+		importers = append(importers, i)
+	}
+	sort.Strings(importers)
+	return importers
+}
+
+func vulnerabilityID(adv Advisory) string {
+	if adv.GithubAdvisoryID != "" {
+		return adv.GithubAdvisoryID
+	}
+	if m := ghsaRegex.FindString(adv.URL); m != "" {
+		return m
+	}
+	return fmt.Sprintf("NPM-%d", adv.ID)
+}
@@ -0,0 +1,119 @@
+package pnpm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"depscanity/internal/model"
+)
+
+func TestParsePnpmLock_V6Workspace(t *testing.T) {
+	lock, err := parsePnpmLock(filepath.Join("testdata", "pnpm_lock_v6.yaml"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	if lock.Version != "6.0" {
+		t.Errorf("expected lockfile version 6.0, got %s", lock.Version)
+	}
+	if v := lock.Packages["react-dom"]; len(v) != 1 || v[0] != "18.2.0" {
+		t.Errorf("expected peer suffix stripped, got %v", v)
+	}
+	if v := lock.Packages["@babel/core"]; len(v) != 1 || v[0] != "7.22.0" {
+		t.Errorf("expected scoped package, got %v", v)
+	}
+	if lock.Importers["packages/api"]["express"] != "4.17.1" {
+		t.Errorf("expected express in packages/api importer, got %v", lock.Importers["packages/api"])
+	}
+	if _, ok := lock.Importers["packages/api"]["shared"]; ok {
+		t.Error("link: dependencies should be skipped")
+	}
+	if lock.Importers["."]["typescript"] != "5.2.2" {
+		t.Errorf("expected root importer devDependency, got %v", lock.Importers["."])
+	}
+}
+
+func TestParsePnpmLock_V9AndV5(t *testing.T) {
+	lock, err := parsePnpmLock(filepath.Join("testdata", "pnpm_lock_v9.yaml"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if v := lock.Packages["lodash"]; len(v) != 1 || v[0] != "4.17.20" {
+		t.Errorf("v9: unexpected lodash versions %v", v)
+	}
+	if v := lock.Packages["@babel/core"]; len(v) != 1 || v[0] != "7.22.0" {
+		t.Errorf("v9: expected snapshots to collapse into one version, got %v", v)
+	}
+
+	lock, err = parsePnpmLock(filepath.Join("testdata", "pnpm_lock_v5.yaml"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if v := lock.Packages["@types/node"]; len(v) != 1 || v[0] != "18.0.0" {
+		t.Errorf("v5: unexpected @types/node versions %v", v)
+	}
+	if lock.Importers["."]["minimist"] != "1.2.5" {
+		t.Errorf("v5: expected top-level dependency on root importer, got %v", lock.Importers["."])
+	}
+}
+
+func TestParsePnpmAudit(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "pnpm_audit.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParsePnpmAudit(string(data), filepath.Join("testdata", "pnpm_lock_v6.yaml"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+
+	qs := findings[0]
+	if qs.Package != "qs" || qs.VulnerabilityID != "GHSA-hrpp-h998-j3pp" || qs.Severity != model.SeverityHigh {
+		t.Errorf("unexpected qs finding: %s %s %s", qs.Package, qs.VulnerabilityID, qs.Severity)
+	}
+	if qs.FixedVersion == nil || *qs.FixedVersion != "6.7.3" {
+		t.Errorf("expected fix from actions, got %v", qs.FixedVersion)
+	}
+	if importers, _ := qs.Metadata["importers"].([]string); len(importers) != 1 || importers[0] != "packages/api" {
+		t.Errorf("expected packages/api importer from audit path, got %v", qs.Metadata["importers"])
+	}
+
+	// No audit findings: installed version and importer come from the lockfile
+	express := findings[1]
+	if express.VulnerabilityID != "NPM-1099" || express.InstalledVersion != "4.17.1" {
+		t.Errorf("unexpected express finding: %s %s", express.VulnerabilityID, express.InstalledVersion)
+	}
+	if importers, _ := express.Metadata["importers"].([]string); len(importers) != 1 || importers[0] != "packages/api" {
+		t.Errorf("expected packages/api importer from lockfile, got %v", express.Metadata["importers"])
+	}
+}
+
+func TestParsePnpmAudit_InstallAction(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "pnpm_audit_install.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParsePnpmAudit(string(data), filepath.Join("testdata", "pnpm_lock_v6.yaml"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+
+	// The install action upgrades the parent express; the transitive fix comes from patched_versions
+	bodyParser := findings[0]
+	if bodyParser.Package != "body-parser" || bodyParser.FixedVersion == nil || *bodyParser.FixedVersion != "1.20.3" {
+		t.Errorf("expected body-parser fixed in 1.20.3, got %s %v", bodyParser.Package, bodyParser.FixedVersion)
+	}
+	express := findings[1]
+	if express.Package != "express" || express.FixedVersion == nil || *express.FixedVersion != "4.21.0" {
+		t.Errorf("expected express fixed by the install target 4.21.0, got %s %v", express.Package, express.FixedVersion)
+	}
+}
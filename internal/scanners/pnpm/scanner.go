@@ -0,0 +1,99 @@
+package pnpm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+)
+
+// MaxLockfiles caps how many pnpm-lock.yaml files are audited per run.
+const MaxLockfiles = 10
+
+// Scanner audits pnpm-lock.yaml files with pnpm audit.
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the pnpm scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "pnpm" }
+
+func (s *Scanner) MaxTargets() int { return MaxLockfiles }
+
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	return det.Pnpm
+}
+
+func (s *Scanner) Scan(ctx context.Context, lockPath string) ([]model.Finding, []report.ScannerError) {
+	findings, err := ScanPnpm(ctx, lockPath, s.opts)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "pnpm",
+			Location: lockPath,
+			Message:  err.Error(),
+		}}
+	}
+	return findings, nil
+}
+
+// ScanPnpm executes pnpm audit and parses the results.
+func ScanPnpm(ctx context.Context, lockPath string, opts scanners.Options) ([]model.Finding, error) {
+	// 1. Setup paths
+	workDir := filepath.Dir(lockPath)
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
+	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create raw output dir: %w", err)
+	}
+
+	// 2. Check pnpm existence
+	if _, err := exec.LookPath("pnpm"); err != nil {
+		return nil, fmt.Errorf("pnpm executable not found in PATH")
+	}
+
+	// 3. Execution (pnpm audit reads the lockfile, no install needed)
+	res, err := opts.RunPhase(ctx, "pnpm", scanners.PhaseAudit, lockPath, "pnpm", []string{"audit", "--json"}, workDir)
+	// pnpm exits non-zero when vulnerabilities are found
+	if res.ExitCode == 124 {
+		return nil, fmt.Errorf("pnpm audit timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit))
+	}
+	if res.ExitCode == 127 {
+		return nil, fmt.Errorf("pnpm audit failed execution (code %d): %v", res.ExitCode, err)
+	}
+
+	// 4. Save raw output
+	sanitizedName := sanitizePath(workDir)
+	rawFile := filepath.Join(rawOutDir, fmt.Sprintf("pnpm-%s.json", sanitizedName))
+	if err := os.WriteFile(rawFile, []byte(res.Stdout), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write raw output: %w", err)
+	}
+
+	// 5. Parse
+	findings, err := ParsePnpmAudit(res.Stdout, lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	return findings, nil
+}
+
+func sanitizePath(path string) string {
+	s := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, path)
+	return strings.Trim(s, "_")
+}
@@ -0,0 +1,220 @@
+package scanners
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+)
+
+// Job is a single scanner/target pair.
+type Job struct {
+	Scanner Scanner
+	Target  string
+}
+
+// Result holds the outcome of a Job.
+type Result struct {
+	Job
+	Findings []model.Finding
+	Errors   []report.ScannerError
+	Duration time.Duration
+	// Timings lists the recorded phases followed by the target total.
+	Timings []report.PhaseTiming
+}
+
+// PhaseTotal names the timing record covering a whole target.
+const PhaseTotal = "total"
+
+// Plan expands the scanners into jobs, honouring each scanner's target limit.
+// It also returns which scanners had anything to scan, keyed by name.
+func Plan(list []Scanner, det detect.DetectionResult, out io.Writer) ([]Job, map[string]bool) {
+	var jobs []Job
+	toolsRun := make(map[string]bool)
+
+	for _, s := range list {
+		targets := s.Targets(det)
+		toolsRun[s.Name()] = len(targets) > 0
+		if len(targets) == 0 {
+			continue
+		}
+
+		limit := len(targets)
+		if l, ok := s.(Limiter); ok && l.MaxTargets() > 0 && l.MaxTargets() < limit {
+			limit = l.MaxTargets()
+		}
+		fmt.Fprintf(out, "Planned %d %s targets.\n", limit, s.Name())
+		if len(targets) > limit {
+			fmt.Fprintf(out, "  ... skipped %d remaining targets (limit %d)\n", len(targets)-limit, limit)
+		}
+
+		for _, target := range targets[:limit] {
+			jobs = append(jobs, Job{Scanner: s, Target: target})
+		}
+	}
+
+	return jobs, toolsRun
+}
+
+// Run executes the jobs with at most parallel workers (sequentially when parallel <= 1).
+// Each target is bounded by its scanner budget from timeouts, within the ceiling of ctx.
+// Jobs sharing a lock group (see Serializer) run one at a time: a free worker takes the first
+// job whose group is not held, so other scanners keep running while a group is busy.
+// Results are returned in job order regardless of completion order, and every
+// progress line is prefixed with the job number and scanner name.
+func Run(ctx context.Context, jobs []Job, parallel int, timeouts Timeouts, out io.Writer) []Result {
+	results := make([]Result, len(jobs))
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var outMu sync.Mutex
+	printf := func(format string, args ...any) {
+		outMu.Lock()
+		defer outMu.Unlock()
+		fmt.Fprintf(out, format, args...)
+	}
+
+	groups := make([]string, len(jobs))
+	for i, job := range jobs {
+		if s, ok := job.Scanner.(Serializer); ok {
+			groups[i] = s.LockGroup(job.Target)
+		}
+	}
+	locks := newGroupLocks()
+	ctx = context.WithValue(withCeiling(ctx), groupLocksKey{}, locks)
+
+	taken := make([]bool, len(jobs))
+	// next hands out the first job not taken yet whose group is free, waiting while every
+	// remaining job is blocked on a held group. It returns -1 once all jobs are taken.
+	next := func() int {
+		locks.mu.Lock()
+		defer locks.mu.Unlock()
+		for {
+			pending := false
+			for i := range jobs {
+				if taken[i] {
+					continue
+				}
+				pending = true
+				if groups[i] != "" && locks.held[groups[i]] {
+					continue
+				}
+				taken[i] = true
+				if groups[i] != "" {
+					locks.held[groups[i]] = true
+				}
+				return i
+			}
+			if !pending {
+				return -1
+			}
+			locks.cond.Wait()
+		}
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < parallel && w < len(jobs); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := next(); i >= 0; i = next() {
+				job := jobs[i]
+				prefix := fmt.Sprintf("[%d/%d] [%s]", i+1, len(jobs), job.Scanner.Name())
+				printf("%s Scanning %s ...\n", prefix, job.Target)
+
+				jobCtx, cancel := ctx, context.CancelFunc(func() {})
+				if budget := timeouts.Scanner[job.Scanner.Name()]; budget > 0 {
+					jobCtx, cancel = context.WithTimeout(ctx, budget)
+				}
+				rec := &timings{}
+
+				start := time.Now()
+				findings, errs := job.Scanner.Scan(withTimings(jobCtx, rec), job.Target)
+				duration := time.Since(start)
+				timedOut := jobCtx.Err() == context.DeadlineExceeded
+				cancel()
+				if groups[i] != "" {
+					locks.release(groups[i])
+				}
+
+				if timedOut {
+					printf("%s Timed out after %s (%s)\n", prefix, duration.Round(time.Millisecond), TimeoutCause(jobCtx, ""))
+				}
+
+				for _, e := range errs {
+					printf("%s Error: %s: %s\n", prefix, e.Location, e.Message)
+				}
+				if len(errs) == 0 {
+					printf("%s OK %s (%d findings, %s)\n", prefix, job.Target, len(findings), duration.Round(time.Millisecond))
+				}
+
+				results[i] = Result{
+					Job:      job,
+					Findings: findings,
+					Errors:   errs,
+					Duration: duration,
+					Timings: append(rec.list, report.PhaseTiming{
+						Source:     job.Scanner.Name(),
+						Location:   job.Target,
+						Phase:      PhaseTotal,
+						DurationMs: duration.Milliseconds(),
+						TimedOut:   timedOut,
+					}),
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+type groupLocksKey struct{}
+
+// groupLocks records which lock groups are held, by Run for a whole job or by AcquireGroup.
+type groupLocks struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	held map[string]bool
+}
+
+func newGroupLocks() *groupLocks {
+	g := &groupLocks{held: make(map[string]bool)}
+	g.cond = sync.NewCond(&g.mu)
+	return g
+}
+
+func (g *groupLocks) acquire(group string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.held[group] {
+		g.cond.Wait()
+	}
+	g.held[group] = true
+}
+
+func (g *groupLocks) release(group string) {
+	g.mu.Lock()
+	delete(g.held, group)
+	g.mu.Unlock()
+	g.cond.Broadcast()
+}
+
+// AcquireGroup holds a lock group for part of a Scan whose target Run left unlocked (e.g. the
+// trivy scan after an image build) and returns the function releasing it. It waits while
+// another job holds the group; outside Run it does nothing.
+func AcquireGroup(ctx context.Context, group string) (release func()) {
+	locks, ok := ctx.Value(groupLocksKey{}).(*groupLocks)
+	if !ok {
+		return func() {}
+	}
+	locks.acquire(group)
+	return func() { locks.release(group) }
+}
@@ -0,0 +1,184 @@
+package scanners
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+)
+
+type slowScanner struct {
+	targets []string
+	limit   int
+	active  atomic.Int32
+	peak    atomic.Int32
+}
+
+func (s *slowScanner) Name() string { return "slow" }
+
+func (s *slowScanner) MaxTargets() int { return s.limit }
+
+func (s *slowScanner) Targets(det detect.DetectionResult) []string { return s.targets }
+
+func (s *slowScanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	n := s.active.Add(1)
+	defer s.active.Add(-1)
+	for {
+		p := s.peak.Load()
+		if n <= p || s.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+
+	// Later targets finish first to exercise result ordering
+	if target == "a" {
+		time.Sleep(30 * time.Millisecond)
+	}
+	if target == "c" {
+		return nil, []report.ScannerError{{Source: "slow", Location: target, Message: "boom"}}
+	}
+	return []model.Finding{{Source: "slow", Package: target}}, nil
+}
+
+func TestPlan_Limit(t *testing.T) {
+	s := &slowScanner{targets: []string{"a", "b", "c"}, limit: 2}
+	empty := &fakeScanner{name: "empty"}
+
+	jobs, tools := Plan([]Scanner{s, &noTargets{empty}}, detect.DetectionResult{}, io.Discard)
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 jobs after limit, got %d", len(jobs))
+	}
+	if !tools["slow"] || tools["empty"] {
+		t.Errorf("unexpected tools map: %v", tools)
+	}
+}
+
+func TestRun_ParallelKeepsOrder(t *testing.T) {
+	s := &slowScanner{targets: []string{"a", "b", "c", "d"}}
+	jobs, _ := Plan([]Scanner{s}, detect.DetectionResult{}, io.Discard)
+
+	results := Run(context.Background(), jobs, 4, Timeouts{}, io.Discard)
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	for i, want := range []string{"a", "b", "c", "d"} {
+		if results[i].Target != want {
+			t.Errorf("result %d: expected target %s, got %s", i, want, results[i].Target)
+		}
+	}
+	if len(results[2].Errors) != 1 || len(results[2].Findings) != 0 {
+		t.Errorf("expected error result for target c, got %+v", results[2])
+	}
+	if results[0].Findings[0].Package != "a" {
+		t.Errorf("findings not attached to their job")
+	}
+	if s.peak.Load() < 2 {
+		t.Errorf("expected concurrent execution, peak was %d", s.peak.Load())
+	}
+}
+
+func TestRun_Sequential(t *testing.T) {
+	s := &slowScanner{targets: []string{"a", "b", "d"}}
+	jobs, _ := Plan([]Scanner{s}, detect.DetectionResult{}, io.Discard)
+
+	Run(context.Background(), jobs, 0, Timeouts{}, io.Discard)
+	if s.peak.Load() != 1 {
+		t.Errorf("expected sequential execution, peak was %d", s.peak.Load())
+	}
+}
+
+type noTargets struct{ *fakeScanner }
+
+func (n *noTargets) Targets(det detect.DetectionResult) []string { return nil }
+
+type serialScanner struct{ *slowScanner }
+
+func (s *serialScanner) LockGroup(target string) string { return "shared" }
+
+func TestRun_LockGroupSerializes(t *testing.T) {
+	// Two scanners of the same group count their active scans together
+	s := &slowScanner{targets: []string{"a", "b", "d"}}
+	jobs, _ := Plan([]Scanner{&serialScanner{s}, &serialScanner{s}}, detect.DetectionResult{}, io.Discard)
+
+	results := Run(context.Background(), jobs, 4, Timeouts{}, io.Discard)
+	if len(results) != 6 {
+		t.Fatalf("expected 6 results, got %d", len(results))
+	}
+	if s.peak.Load() != 1 {
+		t.Errorf("expected lock group to serialize jobs, peak was %d", s.peak.Load())
+	}
+}
+
+// gatedScanner holds the "shared" group; its first target waits for the unlocked job to finish.
+type gatedScanner struct {
+	unlockedDone chan struct{}
+	waited       atomic.Bool
+}
+
+func (s *gatedScanner) Name() string { return "gated" }
+
+func (s *gatedScanner) LockGroup(target string) string { return "shared" }
+
+func (s *gatedScanner) Targets(det detect.DetectionResult) []string { return []string{"a", "b"} }
+
+func (s *gatedScanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	if target == "a" {
+		select {
+		case <-s.unlockedDone:
+			s.waited.Store(true)
+		case <-time.After(2 * time.Second):
+		}
+	}
+	return nil, nil
+}
+
+type doneScanner struct{ done chan struct{} }
+
+func (s *doneScanner) Name() string { return "free" }
+
+func (s *doneScanner) Targets(det detect.DetectionResult) []string { return []string{"c"} }
+
+func (s *doneScanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	close(s.done)
+	return nil, nil
+}
+
+func TestRun_LockGroupLeavesWorkersFree(t *testing.T) {
+	done := make(chan struct{})
+	gated := &gatedScanner{unlockedDone: done}
+	jobs, _ := Plan([]Scanner{gated, &doneScanner{done: done}}, detect.DetectionResult{}, io.Discard)
+
+	// The second worker must skip the blocked "b" and run the unlocked job while "a" holds the group
+	Run(context.Background(), jobs, 2, Timeouts{}, io.Discard)
+	if !gated.waited.Load() {
+		t.Error("expected the unlocked job to finish while the lock group was held")
+	}
+}
+
+func TestAcquireGroup(t *testing.T) {
+	s := &slowScanner{targets: []string{"a", "b", "d"}}
+	held := &acquiringScanner{s}
+	jobs, _ := Plan([]Scanner{held}, detect.DetectionResult{}, io.Discard)
+
+	Run(context.Background(), jobs, 3, Timeouts{}, io.Discard)
+	if s.peak.Load() != 1 {
+		t.Errorf("expected AcquireGroup to serialize the held section, peak was %d", s.peak.Load())
+	}
+
+	// Outside Run there is nothing to wait for
+	AcquireGroup(context.Background(), "shared")()
+}
+
+// acquiringScanner leaves its targets unlocked and holds the group inside Scan.
+type acquiringScanner struct{ *slowScanner }
+
+func (s *acquiringScanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	release := AcquireGroup(ctx, "shared")
+	defer release()
+	return s.slowScanner.Scan(ctx, target)
+}
@@ -0,0 +1,124 @@
+package scanners
+
+import (
+	"context"
+	"sync"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+)
+
+// Options carries the run-wide settings handed to every scanner factory.
+type Options struct {
+	Root        string
+	OutDir      string
+	Timeouts    Timeouts
+	NoOSV       bool
+	AdvisoryDB  string
+	RustsecDB   string
+	RubyDB      string
+	NugetHealth bool
+	NoContainer bool
+	Images      []string
+	DockerBuild bool
+	// Dockerfile, BuildContext, BuildTarget, Platform and BuildArgs tune the image builds
+	Dockerfile   string
+	BuildContext string
+	BuildTarget  string
+	Platform     string
+	BuildArgs    map[string]string
+	// ImageArchive (docker save / OCI tarball) and OCILayout are scanned with trivy image --input
+	ImageArchive string
+	OCILayout    string
+	// BaseImages scans the images of Dockerfile FROM lines without building
+	BaseImages bool
+	// TrivyFS and TrivyConfig run trivy fs / trivy config on the repository root
+	TrivyFS     bool
+	TrivyConfig bool
+	// ComposeProfiles activates compose services that declare profiles; ComposeBuild
+	// builds services that have a build: section instead of skipping them.
+	ComposeProfiles []string
+	ComposeBuild    bool
+	// TrivyCacheDir is passed to trivy --cache-dir (trivy's own default when empty);
+	// TrivySkipDBUpdate and TrivyOffline scan with the databases already in the cache.
+	TrivyCacheDir     string
+	TrivySkipDBUpdate bool
+	TrivyOffline      bool
+}
+
+// Scanner is the contract every ecosystem scanner implements.
+type Scanner interface {
+	// Name identifies the scanner in ReportMeta.Tools and ScannerError.Source.
+	Name() string
+	// Targets selects what to scan from the detection result.
+	// An empty result means the scanner has nothing to do for this run.
+	Targets(det detect.DetectionResult) []string
+	// Scan runs the scanner against a single target.
+	Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError)
+}
+
+// Limiter is implemented by scanners that cap the number of targets per run.
+type Limiter interface {
+	MaxTargets() int
+}
+
+// Serializer is implemented by scanners whose tool keeps state shared between targets
+// (the trivy cache lock, the obj/ folders written by dotnet restore). Run never runs two
+// jobs of the same lock group at once, whatever --parallel says; an empty group leaves the
+// target unlocked (a scanner can then hold the group for part of Scan with AcquireGroup).
+type Serializer interface {
+	LockGroup(target string) string
+}
+
+// Factory builds a scanner from the run options.
+type Factory func(opts Options) Scanner
+
+type registration struct {
+	name    string
+	factory Factory
+}
+
+var (
+	mu       sync.Mutex
+	registry []registration
+)
+
+// Register adds a scanner factory to the registry.
+// Scanners run in registration order; registering a name twice replaces the earlier factory.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	for i, r := range registry {
+		if r.name == name {
+			registry[i].factory = factory
+			return
+		}
+	}
+	registry = append(registry, registration{name: name, factory: factory})
+}
+
+// New instantiates every registered scanner with the given options.
+func New(opts Options) []Scanner {
+	mu.Lock()
+	defer mu.Unlock()
+
+	result := make([]Scanner, 0, len(registry))
+	for _, r := range registry {
+		result = append(result, r.factory(opts))
+	}
+	return result
+}
+
+// Names returns the registered scanner names in registration order.
+func Names() []string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	names := make([]string, 0, len(registry))
+	for _, r := range registry {
+		names = append(names, r.name)
+	}
+	return names
+}
@@ -0,0 +1,53 @@
+package scanners
+
+import (
+	"context"
+	"testing"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+)
+
+type fakeScanner struct {
+	name string
+	root string
+}
+
+func (f *fakeScanner) Name() string { return f.name }
+
+func (f *fakeScanner) Targets(det detect.DetectionResult) []string { return []string{f.root} }
+
+func (f *fakeScanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	return nil, nil
+}
+
+func TestRegister(t *testing.T) {
+	saved := registry
+	registry = nil
+	defer func() { registry = saved }()
+
+	factory := func(name string) Factory {
+		return func(opts Options) Scanner { return &fakeScanner{name: name, root: opts.Root} }
+	}
+
+	Register("b", factory("b"))
+	Register("a", factory("a"))
+	Register("b", factory("b2"))
+
+	names := Names()
+	if len(names) != 2 || names[0] != "b" || names[1] != "a" {
+		t.Fatalf("expected registration order [b a], got %v", names)
+	}
+
+	list := New(Options{Root: "/repo"})
+	if len(list) != 2 {
+		t.Fatalf("expected 2 scanners, got %d", len(list))
+	}
+	if list[0].Name() != "b2" {
+		t.Errorf("expected re-registered factory to replace the first, got %s", list[0].Name())
+	}
+	if targets := list[1].Targets(detect.DetectionResult{}); len(targets) != 1 || targets[0] != "/repo" {
+		t.Errorf("expected options to reach the factory, got %v", targets)
+	}
+}
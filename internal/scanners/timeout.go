@@ -0,0 +1,133 @@
+package scanners
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	depExec "depscanity/internal/exec"
+	"depscanity/internal/report"
+)
+
+// Phase names used for timeout budgets and timing records.
+const (
+	// PhaseRestore covers dependency installation (npm ci, dotnet restore).
+	PhaseRestore = "restore"
+	// PhaseAudit covers the vulnerability query itself (npm audit, dotnet list, trivy image, ...).
+	PhaseAudit = "audit"
+	// PhaseBuild covers container image builds.
+	PhaseBuild = "build"
+)
+
+// Timeouts holds per-phase and per-scanner budgets. Zero means no budget beyond the global ceiling.
+type Timeouts struct {
+	Restore time.Duration
+	Audit   time.Duration
+	Build   time.Duration
+	// Scanner bounds every target of the named scanner.
+	Scanner map[string]time.Duration
+}
+
+// Phase returns the budget for a phase.
+func (t Timeouts) Phase(phase string) time.Duration {
+	switch phase {
+	case PhaseRestore:
+		return t.Restore
+	case PhaseAudit:
+		return t.Audit
+	case PhaseBuild:
+		return t.Build
+	default:
+		return 0
+	}
+}
+
+// ParseScannerTimeouts parses "npm=120,dotnet=600" (seconds) into per-scanner budgets.
+func ParseScannerTimeouts(spec string) (map[string]time.Duration, error) {
+	result := make(map[string]time.Duration)
+	if strings.TrimSpace(spec) == "" {
+		return result, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid scanner timeout %q (expected name=seconds)", entry)
+		}
+		sec, err := strconv.Atoi(value)
+		if err != nil || sec <= 0 {
+			return nil, fmt.Errorf("invalid scanner timeout %q (expected name=seconds)", entry)
+		}
+		result[name] = time.Duration(sec) * time.Second
+	}
+	return result, nil
+}
+
+type ceilingKey struct{}
+
+// withCeiling records the context holding the global --timeout, so a job context bounded
+// further by its --timeout-scanner budget can tell the two deadlines apart.
+func withCeiling(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ceilingKey{}, ctx)
+}
+
+// TimeoutCause names the budget that stopped a command run by RunPhase under ctx with exit
+// code 124: the phase budget, the --timeout-scanner budget of the job or the global --timeout.
+func TimeoutCause(ctx context.Context, phase string) string {
+	if ctx.Err() == nil {
+		return fmt.Sprintf("phase %s budget", phase)
+	}
+	if ceiling, ok := ctx.Value(ceilingKey{}).(context.Context); ok && ceiling.Err() == nil {
+		return "--timeout-scanner budget"
+	}
+	return "global --timeout"
+}
+
+type timingsKey struct{}
+
+// timings collects the phase timings of a single job.
+type timings struct {
+	mu   sync.Mutex
+	list []report.PhaseTiming
+}
+
+func withTimings(ctx context.Context, t *timings) context.Context {
+	return context.WithValue(ctx, timingsKey{}, t)
+}
+
+func (t *timings) record(pt report.PhaseTiming) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.list = append(t.list, pt)
+}
+
+// RunPhase runs a command under the phase budget from the options (bounded by ctx)
+// and records its duration and outcome for the report.
+func (o Options) RunPhase(ctx context.Context, source, phase, location, name string, args []string, dir string) (depExec.Result, error) {
+	phaseCtx := ctx
+	budget := o.Timeouts.Phase(phase)
+	if budget > 0 {
+		var cancel context.CancelFunc
+		phaseCtx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+
+	res, err := depExec.Run(phaseCtx, name, args, dir)
+
+	if t, ok := ctx.Value(timingsKey{}).(*timings); ok {
+		t.record(report.PhaseTiming{
+			Source:     source,
+			Location:   location,
+			Phase:      phase,
+			Command:    strings.TrimSpace(name + " " + strings.Join(args, " ")),
+			DurationMs: res.Duration.Milliseconds(),
+			ExitCode:   res.ExitCode,
+			TimedOut:   res.ExitCode == 124,
+		})
+	}
+
+	return res, err
+}
@@ -0,0 +1,130 @@
+package scanners
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+)
+
+func TestParseScannerTimeouts(t *testing.T) {
+	got, err := ParseScannerTimeouts("npm=120, dotnet=600")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["npm"] != 120*time.Second || got["dotnet"] != 600*time.Second {
+		t.Errorf("unexpected budgets: %v", got)
+	}
+
+	for _, bad := range []string{"npm", "npm=abc", "=10", "npm=0"} {
+		if _, err := ParseScannerTimeouts(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+type sleepScanner struct {
+	opts Options
+}
+
+func (s *sleepScanner) Name() string { return "sleepy" }
+
+func (s *sleepScanner) Targets(det detect.DetectionResult) []string { return []string{"t"} }
+
+func (s *sleepScanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	s.opts.RunPhase(ctx, "sleepy", PhaseRestore, target, "sleep", []string{"2"}, "")
+	s.opts.RunPhase(ctx, "sleepy", PhaseAudit, target, "true", nil, "")
+	return nil, nil
+}
+
+func TestRun_PhaseTimeout(t *testing.T) {
+	timeouts := Timeouts{Restore: 100 * time.Millisecond}
+	s := &sleepScanner{opts: Options{Timeouts: timeouts}}
+	jobs, _ := Plan([]Scanner{s}, detect.DetectionResult{}, io.Discard)
+
+	results := Run(context.Background(), jobs, 1, timeouts, io.Discard)
+	timings := results[0].Timings
+	if len(timings) != 3 {
+		t.Fatalf("expected restore, audit and total timings, got %+v", timings)
+	}
+	if timings[0].ExitCode == 127 {
+		t.Skip("sleep command not found, skipping timeout test")
+	}
+	if timings[0].Phase != PhaseRestore || !timings[0].TimedOut {
+		t.Errorf("expected restore phase to time out, got %+v", timings[0])
+	}
+	if timings[1].Phase != PhaseAudit || timings[1].TimedOut {
+		t.Errorf("expected audit phase to run after restore timeout, got %+v", timings[1])
+	}
+	if timings[2].Phase != PhaseTotal || timings[2].TimedOut {
+		t.Errorf("expected total record without scanner budget, got %+v", timings[2])
+	}
+}
+
+func TestRun_ScannerBudget(t *testing.T) {
+	timeouts := Timeouts{Scanner: map[string]time.Duration{"sleepy": 100 * time.Millisecond}}
+	s := &sleepScanner{opts: Options{Timeouts: timeouts}}
+	jobs, _ := Plan([]Scanner{s}, detect.DetectionResult{}, io.Discard)
+
+	results := Run(context.Background(), jobs, 1, timeouts, io.Discard)
+	timings := results[0].Timings
+	if timings[0].ExitCode == 127 {
+		t.Skip("sleep command not found, skipping timeout test")
+	}
+	total := timings[len(timings)-1]
+	if !total.TimedOut || total.DurationMs >= 2000 {
+		t.Errorf("expected scanner budget to stop the target early, got %+v", total)
+	}
+}
+
+// causeScanner records which budget stopped its restore phase.
+type causeScanner struct {
+	opts  Options
+	cause string
+}
+
+func (s *causeScanner) Name() string { return "sleepy" }
+
+func (s *causeScanner) Targets(det detect.DetectionResult) []string { return []string{"t"} }
+
+func (s *causeScanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	res, _ := s.opts.RunPhase(ctx, "sleepy", PhaseRestore, target, "sleep", []string{"2"}, "")
+	switch res.ExitCode {
+	case 124:
+		s.cause = TimeoutCause(ctx, PhaseRestore)
+	case 127:
+		s.cause = "no sleep"
+	}
+	return nil, nil
+}
+
+func TestTimeoutCause(t *testing.T) {
+	cases := []struct {
+		name     string
+		timeouts Timeouts
+		ceiling  time.Duration
+		want     string
+	}{
+		{"phase", Timeouts{Restore: 100 * time.Millisecond}, time.Minute, "phase restore budget"},
+		{"scanner", Timeouts{Scanner: map[string]time.Duration{"sleepy": 100 * time.Millisecond}}, time.Minute, "--timeout-scanner budget"},
+		{"global", Timeouts{}, 100 * time.Millisecond, "global --timeout"},
+	}
+	for _, c := range cases {
+		s := &causeScanner{opts: Options{Timeouts: c.timeouts}}
+		jobs, _ := Plan([]Scanner{s}, detect.DetectionResult{}, io.Discard)
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.ceiling)
+		Run(ctx, jobs, 1, c.timeouts, io.Discard)
+		cancel()
+		if s.cause == "no sleep" {
+			t.Skip("sleep command not found, skipping timeout test")
+		}
+		if s.cause != c.want {
+			t.Errorf("%s: TimeoutCause = %q, want %q", c.name, s.cause, c.want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package trivy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"depscanity/internal/dockerfile"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+)
+
+// builders are tried in order; podman and buildah keep images outside the Docker daemon,
+// so their images are exported to a tarball and scanned with trivy image --input.
+var builders = []string{"docker", "podman", "buildah"}
+
+// BuildSpec describes what to build: a context directory plus the optional
+// Dockerfile (relative to the context unless absolute), target stage, platform and build arguments.
+type BuildSpec struct {
+	Context    string
+	Dockerfile string
+	Target     string
+	Platform   string
+	Args       map[string]string
+}
+
+// location names the build in timings and errors: the Dockerfile when known, else the context.
+func (spec BuildSpec) location() string {
+	if spec.Dockerfile == "" {
+		return spec.Context
+	}
+	if filepath.IsAbs(spec.Dockerfile) {
+		return spec.Dockerfile
+	}
+	return filepath.Join(spec.Context, spec.Dockerfile)
+}
+
+// findBuilder returns the first image builder available in PATH.
+func findBuilder() (string, error) {
+	for _, name := range builders {
+		if _, err := exec.LookPath(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no image builder found in PATH (tried %s)", strings.Join(builders, ", "))
+}
+
+// buildArgs returns the command line of builder for the spec.
+func buildArgs(builder string, spec BuildSpec, tag string) []string {
+	args := []string{"build", "-t", tag}
+	if builder == "buildah" {
+		args[0] = "bud"
+	}
+	if spec.Dockerfile != "" {
+		args = append(args, "-f", spec.location())
+	}
+	if spec.Target != "" {
+		args = append(args, "--target", spec.Target)
+	}
+	if spec.Platform != "" {
+		args = append(args, "--platform", spec.Platform)
+	}
+	names := make([]string, 0, len(spec.Args))
+	for name := range spec.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		args = append(args, "--build-arg", name+"="+spec.Args[name])
+	}
+	return append(args, spec.Context)
+}
+
+// BuildImage builds the spec with the first available builder and tags the result.
+// The build log is saved to raw/docker-build.txt (raw/docker-build-<tag>.txt for other tags than LocalImageTag).
+// It returns the builder used; a non-nil ScannerError means the image is not available.
+func BuildImage(ctx context.Context, spec BuildSpec, tag string, opts scanners.Options) (string, *report.ScannerError) {
+	builder, err := findBuilder()
+	if err != nil {
+		return "", &report.ScannerError{Source: "trivy-build", Location: spec.location(), Message: err.Error()}
+	}
+	fmt.Printf("Building local image %s from %s with %s...\n", tag, spec.location(), builder)
+
+	buildRes, err := opts.RunPhase(ctx, "trivy-build", scanners.PhaseBuild, spec.location(), builder, buildArgs(builder, spec, tag), spec.Context)
+
+	// Save raw build logs, one per built image
+	logName := "docker-build.txt"
+	if tag != LocalImageTag {
+		logName = fmt.Sprintf("docker-build-%s.txt", sanitizePath(tag))
+	}
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
+	_ = os.MkdirAll(rawOutDir, 0755)
+	_ = os.WriteFile(filepath.Join(rawOutDir, logName), []byte(fmt.Sprintf("STDOUT:\n%s\nSTDERR:\n%s\nEXIT: %d\nERROR: %v", buildRes.Stdout, buildRes.Stderr, buildRes.ExitCode, err)), 0644)
+
+	if buildRes.ExitCode == 124 {
+		fmt.Printf("Image build timed out after %s.\n", buildRes.Duration.Round(time.Second))
+		return "", &report.ScannerError{
+			Source:   "trivy-build",
+			Location: spec.location(),
+			Message:  fmt.Sprintf("%s build timed out after %s (%s)", builder, buildRes.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseBuild)),
+		}
+	}
+	if err != nil || buildRes.ExitCode != 0 {
+		fmt.Printf("Image build failed. code=%d err=%v. See report for details.\n", buildRes.ExitCode, err)
+		return "", &report.ScannerError{
+			Source:   "trivy-build",
+			Location: spec.location(),
+			Message:  fmt.Sprintf("%s build failed: %v\nStderr: %s", builder, err, buildRes.Stderr),
+		}
+	}
+
+	fmt.Println("Image build successful.")
+	return builder, nil
+}
+
+// ScanBuiltImage builds the spec as tag and scans the result. Images built by podman or
+// buildah are exported to <out>/images/<tag>.tar first, since trivy reads the Docker daemon.
+// Findings are attributed to the base image or to the Dockerfile line that added their layer.
+// Only the trivy scan holds LockGroup, so callers must leave build targets unlocked.
+func ScanBuiltImage(ctx context.Context, spec BuildSpec, tag string, opts scanners.Options) ([]model.Finding, []report.ScannerError) {
+	builder, buildErr := BuildImage(ctx, spec, tag, opts)
+	if buildErr != nil {
+		return nil, []report.ScannerError{*buildErr}
+	}
+	chain := spec.stages()
+
+	imageArgs := []string{tag}
+	if builder != "docker" {
+		archive, exportErr := exportImage(ctx, builder, tag, spec.location(), opts)
+		if exportErr != nil {
+			return nil, []report.ScannerError{*exportErr}
+		}
+		imageArgs = []string{"--input", archive}
+	}
+
+	release := scanners.AcquireGroup(ctx, LockGroup)
+	findings, scannerErrors := scanImage(ctx, tag, imageArgs, chain, opts)
+	release()
+	if chain != nil {
+		for i := range findings {
+			findings[i].Metadata["dockerfile"] = spec.dockerfilePath()
+		}
+	}
+	return findings, scannerErrors
+}
+
+// dockerfilePath returns the Dockerfile of the build, defaulting to <context>/Dockerfile like docker build.
+func (spec BuildSpec) dockerfilePath() string {
+	if spec.Dockerfile == "" {
+		return filepath.Join(spec.Context, "Dockerfile")
+	}
+	return spec.location()
+}
+
+// stages returns the Dockerfile stages the built image is made of, or nil when the
+// Dockerfile cannot be read (findings then keep their layer without an origin).
+func (spec BuildSpec) stages() []dockerfile.Stage {
+	data, err := os.ReadFile(spec.dockerfilePath())
+	if err != nil {
+		return nil
+	}
+	stages, err := dockerfile.Parse(data, spec.Args)
+	if err != nil {
+		fmt.Printf("  [Trivy] Cannot attribute layers of %s: %v\n", spec.dockerfilePath(), err)
+		return nil
+	}
+	return dockerfile.Chain(stages, spec.Target)
+}
+
+// exportImage saves an image from podman/buildah storage as a docker archive.
+func exportImage(ctx context.Context, builder, tag, location string, opts scanners.Options) (string, *report.ScannerError) {
+	imagesDir := filepath.Join(opts.OutDir, "images")
+	if err := os.MkdirAll(imagesDir, 0755); err != nil {
+		return "", &report.ScannerError{Source: "trivy-build", Location: location, Message: fmt.Sprintf("failed to create images dir: %v", err)}
+	}
+	archive, err := filepath.Abs(filepath.Join(imagesDir, sanitizePath(tag)+".tar"))
+	if err != nil {
+		return "", &report.ScannerError{Source: "trivy-build", Location: location, Message: err.Error()}
+	}
+	_ = os.Remove(archive)
+
+	args := []string{"save", "-o", archive, tag}
+	if builder == "buildah" {
+		args = []string{"push", tag, "docker-archive:" + archive + ":" + tag}
+	}
+	res, err := opts.RunPhase(ctx, "trivy-build", scanners.PhaseBuild, location, builder, args, ".")
+	if err != nil || res.ExitCode != 0 {
+		return "", &report.ScannerError{
+			Source:   "trivy-build",
+			Location: location,
+			Message:  fmt.Sprintf("%s failed to export %s (code %d): %v\nStderr: %s", builder, tag, res.ExitCode, err, res.Stderr),
+		}
+	}
+	return archive, nil
+}
+
+// BuildTag returns the tag for a Dockerfile below root: LocalImageTag for the root Dockerfile,
+// depscanity-<path>:local for the others (e.g. depscanity-services-api:local).
+func BuildTag(root, dockerfile string) string {
+	rel := relPath(root, dockerfile)
+	if strings.EqualFold(filepath.Base(rel), "dockerfile") {
+		rel = filepath.Dir(rel)
+	}
+	if rel == "." {
+		return LocalImageTag
+	}
+
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		}
+		return '-'
+	}, strings.ToLower(filepath.ToSlash(rel)))
+	return "depscanity-" + strings.Trim(name, "-.") + ":local"
+}
+
+// BuildTags returns the BuildTag of each Dockerfile. Dockerfiles whose paths map to the same
+// tag (services/api/Dockerfile and services-api/Dockerfile) get a short hash of their relative
+// path appended (depscanity-services-api-1a2b3c4d:local), so no build replaces another's image.
+func BuildTags(root string, dockerfiles []string) map[string]string {
+	tags := make(map[string]string, len(dockerfiles))
+	users := make(map[string][]string)
+	for _, path := range dockerfiles {
+		tag := BuildTag(root, path)
+		if _, seen := tags[path]; !seen {
+			users[tag] = append(users[tag], path)
+		}
+		tags[path] = tag
+	}
+
+	for tag, paths := range users {
+		if len(paths) < 2 {
+			continue
+		}
+		for _, path := range paths {
+			sum := sha256.Sum256([]byte(filepath.ToSlash(relPath(root, path))))
+			tags[path] = strings.TrimSuffix(tag, ":local") + "-" + hex.EncodeToString(sum[:4]) + ":local"
+		}
+	}
+	return tags
+}
+
+func relPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,61 @@
+package trivy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildTag(t *testing.T) {
+	cases := map[string]string{
+		"/repo/Dockerfile":                   LocalImageTag,
+		"/repo/services/api/Dockerfile":      "depscanity-services-api:local",
+		"/repo/services/API/Dockerfile.prod": "depscanity-services-api-dockerfile.prod:local",
+		"/repo/worker.Dockerfile":            "depscanity-worker.dockerfile:local",
+	}
+	for dockerfile, want := range cases {
+		if got := BuildTag("/repo", dockerfile); got != want {
+			t.Errorf("BuildTag(%s) = %s, want %s", dockerfile, got, want)
+		}
+	}
+}
+
+func TestBuildTags_Collision(t *testing.T) {
+	tags := BuildTags("/repo", []string{
+		"/repo/services/api/Dockerfile",
+		"/repo/services-api/Dockerfile",
+		"/repo/worker/Dockerfile",
+	})
+
+	api, dashed := tags["/repo/services/api/Dockerfile"], tags["/repo/services-api/Dockerfile"]
+	if api == dashed {
+		t.Fatalf("expected distinct tags for colliding paths, got %s twice", api)
+	}
+	for _, tag := range []string{api, dashed} {
+		if !strings.HasPrefix(tag, "depscanity-services-api-") || !strings.HasSuffix(tag, ":local") {
+			t.Errorf("expected hashed depscanity-services-api tag, got %s", tag)
+		}
+	}
+	if tags["/repo/worker/Dockerfile"] != "depscanity-worker:local" {
+		t.Errorf("expected unchanged tag without collision, got %s", tags["/repo/worker/Dockerfile"])
+	}
+}
+
+func TestBuildArgs(t *testing.T) {
+	spec := BuildSpec{
+		Context:    "/repo",
+		Dockerfile: "services/api/Dockerfile",
+		Target:     "runtime",
+		Platform:   "linux/arm64",
+		Args:       map[string]string{"VERSION": "1.2", "BASE": "alpine"},
+	}
+
+	want := []string{"build", "-t", "api:local", "-f", "/repo/services/api/Dockerfile", "--target", "runtime",
+		"--platform", "linux/arm64", "--build-arg", "BASE=alpine", "--build-arg", "VERSION=1.2", "/repo"}
+	if got := buildArgs("docker", spec, "api:local"); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected docker args: %v", got)
+	}
+	if got := buildArgs("buildah", BuildSpec{Context: "/repo"}, "api:local"); !reflect.DeepEqual(got, []string{"bud", "-t", "api:local", "/repo"}) {
+		t.Errorf("unexpected buildah args: %v", got)
+	}
+}
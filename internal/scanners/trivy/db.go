@@ -0,0 +1,232 @@
+package trivy
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+)
+
+// trivyDB describes where a trivy database lives in the cache directory.
+type trivyDB struct {
+	name   string
+	dir    string
+	dbFile string
+}
+
+var (
+	vulnDB = trivyDB{name: "trivy-db", dir: "db", dbFile: "trivy.db"}
+	javaDB = trivyDB{name: "trivy-java-db", dir: "java-db", dbFile: "trivy-java.db"}
+)
+
+// LockGroup is the scanners.Serializer group of every scanner running trivy: trivy locks its
+// cache while scanning, and a second trivy fails with "cache may be in use by another process".
+const LockGroup = "trivy"
+
+// CacheDir returns the trivy cache directory: dir when set, else trivy's default
+// (<user cache dir>/trivy, e.g. ~/.cache/trivy).
+func CacheDir(dir string) string {
+	if dir != "" {
+		return dir
+	}
+	if userCache, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(userCache, "trivy")
+	}
+	return filepath.Join(os.TempDir(), "trivy")
+}
+
+// DBArgs returns the trivy flags for the cache directory and the DB update behaviour.
+// --offline-scan also skips the DB updates, since offline agents cannot download them.
+func DBArgs(opts scanners.Options) []string {
+	var args []string
+	if opts.TrivyCacheDir != "" {
+		args = append(args, "--cache-dir", opts.TrivyCacheDir)
+	}
+	if opts.TrivySkipDBUpdate || opts.TrivyOffline {
+		args = append(args, "--skip-db-update", "--skip-java-db-update")
+	}
+	if opts.TrivyOffline {
+		args = append(args, "--offline-scan")
+	}
+	return args
+}
+
+// CheckDB fails early when DB updates are skipped and the cache holds no vulnerability DB,
+// which trivy would otherwise report as a failed first run.
+func CheckDB(opts scanners.Options) error {
+	if !opts.TrivySkipDBUpdate && !opts.TrivyOffline {
+		return nil
+	}
+	cacheDir := CacheDir(opts.TrivyCacheDir)
+	if _, err := os.Stat(filepath.Join(cacheDir, vulnDB.dir, vulnDB.dbFile)); err != nil {
+		return fmt.Errorf("no trivy DB in %s and DB updates are skipped; install one with `depscanity db import`", cacheDir)
+	}
+	return nil
+}
+
+// Databases returns the trivy databases installed in the cache directory.
+func Databases(cacheDir string) []report.DBInfo {
+	var infos []report.DBInfo
+	for _, db := range []trivyDB{vulnDB, javaDB} {
+		if info, err := readDBInfo(CacheDir(cacheDir), db); err == nil {
+			infos = append(infos, *info)
+		}
+	}
+	return infos
+}
+
+// ImportDB installs a trivy DB archive into <cacheDir>/db, or a Java DB archive into
+// <cacheDir>/java-db when java is set. The archive is the db.tar.gz / javadb.tar.gz layer of
+// the trivy-db / trivy-java-db OCI artifacts (a plain tar is accepted too); the previous DB
+// is only replaced once the archive has been extracted completely.
+func ImportDB(archive, cacheDir string, java bool) (*report.DBInfo, error) {
+	db := vulnDB
+	if java {
+		db = javaDB
+	}
+	cacheDir = CacheDir(cacheDir)
+	target := filepath.Join(cacheDir, db.dir)
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+
+	tmp, err := os.MkdirTemp(cacheDir, db.dir+"-import-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	if err := os.Chmod(tmp, 0755); err != nil {
+		return nil, err
+	}
+
+	if err := extractDB(archive, tmp, db); err != nil {
+		return nil, err
+	}
+	if err := stampDownloadedAt(filepath.Join(tmp, "metadata.json")); err != nil {
+		return nil, err
+	}
+
+	if err := os.RemoveAll(target); err != nil {
+		return nil, fmt.Errorf("failed to remove previous %s: %w", db.name, err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		return nil, fmt.Errorf("failed to install %s: %w", db.name, err)
+	}
+	return readDBInfo(cacheDir, db)
+}
+
+// extractDB extracts the database file and metadata.json of the archive into dir.
+// Other entries are ignored and directories inside the archive are flattened.
+func extractDB(archive, dir string, db trivyDB) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = bufio.NewReader(f)
+	if magic, _ := r.(*bufio.Reader).Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", archive, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	found := make(map[string]bool)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", archive, err)
+		}
+		name := path.Base(hdr.Name)
+		if hdr.Typeflag != tar.TypeReg || (name != db.dbFile && name != "metadata.json") {
+			continue
+		}
+
+		out, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, tr)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", name, err)
+		}
+		found[name] = true
+	}
+
+	for _, name := range []string{db.dbFile, "metadata.json"} {
+		if !found[name] {
+			return fmt.Errorf("%s is not a %s archive: no %s", archive, db.name, name)
+		}
+	}
+	return nil
+}
+
+// stampDownloadedAt records the import time as DownloadedAt, like trivy does after a download.
+func stampDownloadedAt(metadataPath string) error {
+	data, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return err
+	}
+	var metadata map[string]any
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return fmt.Errorf("invalid metadata.json: %w", err)
+	}
+	metadata["DownloadedAt"] = time.Now().UTC().Format(time.RFC3339Nano)
+	data, err = json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(metadataPath, data, 0644)
+}
+
+// readDBInfo reads the metadata.json of an installed database.
+func readDBInfo(cacheDir string, db trivyDB) (*report.DBInfo, error) {
+	dir := filepath.Join(cacheDir, db.dir)
+	data, err := os.ReadFile(filepath.Join(dir, "metadata.json"))
+	if err != nil {
+		return nil, err
+	}
+	var metadata struct {
+		Version      int       `json:"Version"`
+		UpdatedAt    time.Time `json:"UpdatedAt"`
+		NextUpdate   time.Time `json:"NextUpdate"`
+		DownloadedAt time.Time `json:"DownloadedAt"`
+	}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid metadata.json: %w", err)
+	}
+
+	info := &report.DBInfo{
+		Name:      db.name,
+		Path:      dir,
+		Version:   metadata.Version,
+		UpdatedAt: metadata.UpdatedAt.Format(time.RFC3339),
+	}
+	if !metadata.NextUpdate.IsZero() {
+		info.NextUpdate = metadata.NextUpdate.Format(time.RFC3339)
+	}
+	if !metadata.DownloadedAt.IsZero() {
+		info.DownloadedAt = metadata.DownloadedAt.Format(time.RFC3339)
+	}
+	return info, nil
+}
@@ -0,0 +1,101 @@
+package trivy
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"depscanity/internal/scanners"
+)
+
+// writeDBArchive writes a db.tar.gz-like archive with the given files.
+func writeDBArchive(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "db.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestImportDB(t *testing.T) {
+	archive := writeDBArchive(t, map[string]string{
+		"trivy.db":      "bolt",
+		"metadata.json": `{"Version":2,"NextUpdate":"2024-05-03T00:00:00Z","UpdatedAt":"2024-05-02T06:12:41Z","DownloadedAt":"0001-01-01T00:00:00Z"}`,
+	})
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+
+	info, err := ImportDB(archive, cacheDir, false)
+	if err != nil {
+		t.Fatalf("ImportDB failed: %v", err)
+	}
+	if info.Name != "trivy-db" || info.Version != 2 || info.UpdatedAt != "2024-05-02T06:12:41Z" || info.DownloadedAt == "" {
+		t.Errorf("unexpected DB info: %+v", info)
+	}
+	if data, err := os.ReadFile(filepath.Join(cacheDir, "db", "trivy.db")); err != nil || string(data) != "bolt" {
+		t.Errorf("expected trivy.db in the cache, got %q (%v)", data, err)
+	}
+	if err := CheckDB(scanners.Options{TrivyCacheDir: cacheDir, TrivyOffline: true}); err != nil {
+		t.Errorf("expected the imported DB to satisfy CheckDB: %v", err)
+	}
+	if dbs := Databases(cacheDir); len(dbs) != 1 || dbs[0].Name != "trivy-db" {
+		t.Errorf("expected the imported DB to be listed, got %+v", dbs)
+	}
+
+	// A Java DB archive is rejected as trivy DB and leaves the installed DB untouched
+	javaArchive := writeDBArchive(t, map[string]string{
+		"javadb/trivy-java.db": "bolt",
+		"javadb/metadata.json": `{"Version":1,"UpdatedAt":"2024-05-01T01:02:03Z"}`,
+	})
+	if _, err := ImportDB(javaArchive, cacheDir, false); err == nil || !strings.Contains(err.Error(), "no trivy.db") {
+		t.Errorf("expected missing trivy.db error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "db", "trivy.db")); err != nil {
+		t.Errorf("expected the previous DB to be kept: %v", err)
+	}
+
+	info, err = ImportDB(javaArchive, cacheDir, true)
+	if err != nil {
+		t.Fatalf("ImportDB (java) failed: %v", err)
+	}
+	if info.Name != "trivy-java-db" || info.Path != filepath.Join(cacheDir, "java-db") {
+		t.Errorf("unexpected Java DB info: %+v", info)
+	}
+}
+
+func TestDBArgs(t *testing.T) {
+	if args := DBArgs(scanners.Options{}); len(args) != 0 {
+		t.Errorf("expected no args by default, got %v", args)
+	}
+	if err := CheckDB(scanners.Options{TrivyCacheDir: t.TempDir(), TrivySkipDBUpdate: true}); err == nil {
+		t.Errorf("expected CheckDB to fail on an empty cache")
+	}
+
+	got := DBArgs(scanners.Options{TrivyCacheDir: "/cache", TrivyOffline: true})
+	want := []string{"--cache-dir", "/cache", "--skip-db-update", "--skip-java-db-update", "--offline-scan"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DBArgs = %v, want %v", got, want)
+	}
+}
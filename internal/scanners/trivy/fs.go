@@ -0,0 +1,171 @@
+package trivy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+)
+
+// nativeTypes are the trivy result types (lockfile formats) that DepScanity already
+// scans with a dedicated scanner; trivy fs findings for them are dropped.
+var nativeTypes = map[string]bool{
+	"npm": true, "yarn": true, "pnpm": true, "bun": true,
+	"nuget": true, "dotnet-core": true, "packages-props": true,
+	"gomod": true, "pip": true, "pipenv": true, "poetry": true, "uv": true,
+	"pom": true, "gradle": true, "cargo": true, "composer": true, "bundler": true,
+}
+
+// FsScanner runs trivy against the repository itself: trivy fs for lockfiles of
+// ecosystems without a native scanner, or trivy config for IaC misconfigurations.
+type FsScanner struct {
+	opts   scanners.Options
+	config bool
+}
+
+// NewFs returns the trivy fs scanner (enabled by --trivy-fs).
+func NewFs(opts scanners.Options) scanners.Scanner {
+	return &FsScanner{opts: opts}
+}
+
+// NewConfig returns the trivy config scanner (enabled by --trivy-config).
+func NewConfig(opts scanners.Options) scanners.Scanner {
+	return &FsScanner{opts: opts, config: true}
+}
+
+func (s *FsScanner) Name() string {
+	if s.config {
+		return "trivy-config"
+	}
+	return "trivy-fs"
+}
+
+func (s *FsScanner) LockGroup(target string) string { return LockGroup }
+
+// Targets returns the repository root when the mode is enabled.
+func (s *FsScanner) Targets(det detect.DetectionResult) []string {
+	if (s.config && s.opts.TrivyConfig) || (!s.config && s.opts.TrivyFS) {
+		return []string{s.opts.Root}
+	}
+	return nil
+}
+
+func (s *FsScanner) Scan(ctx context.Context, root string) ([]model.Finding, []report.ScannerError) {
+	var findings []model.Finding
+	var err error
+	if s.config {
+		findings, err = ScanTrivyConfig(ctx, root, s.opts)
+	} else {
+		findings, err = ScanTrivyFs(ctx, root, s.opts)
+	}
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   s.Name(),
+			Location: root,
+			Message:  err.Error(),
+		}}
+	}
+	return findings, nil
+}
+
+// ScanTrivyFs runs trivy fs on the repository and returns the vulnerabilities of
+// lockfiles that no native scanner covers.
+func ScanTrivyFs(ctx context.Context, root string, opts scanners.Options) ([]model.Finding, error) {
+	if err := CheckDB(opts); err != nil {
+		return nil, err
+	}
+	args := append([]string{"fs", "--scanners", "vuln", "--format", "json", "--quiet"}, DBArgs(opts)...)
+	args = append(args, root)
+	output, err := runTrivyRepo(ctx, "trivy-fs", root, args, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var report TrivyReport
+	if err := unmarshalReport(output, &report); err != nil {
+		return nil, err
+	}
+	findings := convertVulnerabilities(nonNativeResults(report.Results))
+	for i := range findings {
+		findings[i].Source = "trivy-fs"
+		findings[i].Location = filepath.Join(root, findings[i].Location)
+	}
+	return findings, nil
+}
+
+// ScanTrivyConfig runs trivy config on the repository and returns misconfiguration
+// findings for Dockerfiles, Kubernetes manifests, Terraform and other IaC files.
+func ScanTrivyConfig(ctx context.Context, root string, opts scanners.Options) ([]model.Finding, error) {
+	args := []string{"config", "--format", "json", "--quiet"}
+	if opts.TrivyCacheDir != "" {
+		args = append(args, "--cache-dir", opts.TrivyCacheDir)
+	}
+	// trivy config downloads its checks bundle unless told otherwise
+	if opts.TrivySkipDBUpdate || opts.TrivyOffline {
+		args = append(args, "--skip-check-update")
+	}
+	args = append(args, root)
+	output, err := runTrivyRepo(ctx, "trivy-config", root, args, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := ParseTrivyMisconfigurations(output)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+	for i := range findings {
+		findings[i].Source = "trivy-config"
+		findings[i].Location = filepath.Join(root, findings[i].Location)
+	}
+	return findings, nil
+}
+
+// nonNativeResults drops the results of lockfile types covered by a native scanner.
+func nonNativeResults(results []TrivyResult) []TrivyResult {
+	var kept []TrivyResult
+	for _, result := range results {
+		if !nativeTypes[result.Type] {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
+
+// runTrivyRepo runs trivy with args and saves the JSON output to raw/<source>.json.
+func runTrivyRepo(ctx context.Context, source, root string, args []string, opts scanners.Options) (string, error) {
+	// 1. Setup paths
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
+	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create raw output dir: %w", err)
+	}
+
+	// 2. Check trivy existence
+	if _, err := exec.LookPath("trivy"); err != nil {
+		return "", fmt.Errorf("trivy executable not found in PATH")
+	}
+
+	// 3. Execution
+	res, err := opts.RunPhase(ctx, source, scanners.PhaseAudit, root, "trivy", args, root)
+	if res.ExitCode == 124 {
+		return "", fmt.Errorf("trivy %s timed out after %s (%s)", args[0], res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit))
+	}
+	if res.ExitCode == 127 {
+		return "", fmt.Errorf("trivy failed execution (code %d): %v", res.ExitCode, err)
+	}
+
+	// 4. Save raw
+	_ = os.WriteFile(filepath.Join(rawOutDir, source+".json"), []byte(res.Stdout), 0644)
+
+	if res.ExitCode != 0 && res.Stdout == "" {
+		return "", fmt.Errorf("trivy %s failed (code %d): %v\nStderr: %s", args[0], res.ExitCode, err, res.Stderr)
+	}
+	return res.Stdout, nil
+}
@@ -0,0 +1,179 @@
+package trivy
+
+import (
+	"strings"
+
+	"depscanity/internal/dockerfile"
+	"depscanity/internal/model"
+)
+
+// Layer origins recorded in the layer_origin metadata of image findings.
+const (
+	OriginBaseImage = "base image"
+	OriginAddedByUs = "added by us"
+)
+
+// ImageConfig is the part of the image config that trivy embeds in image reports: the
+// history shown by docker history and the DiffIDs of the filesystem layers.
+type ImageConfig struct {
+	History []ImageHistory `json:"history"`
+	RootFS  struct {
+		DiffIDs []string `json:"diff_ids"`
+	} `json:"rootfs"`
+}
+
+// ImageHistory is one history entry; entries with EmptyLayer (ENV, CMD, ...) have no layer.
+type ImageHistory struct {
+	CreatedBy  string `json:"created_by"`
+	EmptyLayer bool   `json:"empty_layer"`
+}
+
+// Layer is a filesystem layer of an image with the history entry that created it.
+type Layer struct {
+	// Index is the 1-based position of the layer, from the bottom of the image.
+	Index     int
+	DiffID    string
+	CreatedBy string
+	// Origin is OriginBaseImage or OriginAddedByUs once matched against the Dockerfile.
+	Origin string
+	// Line is the Dockerfile line of the instruction that created the layer (OriginAddedByUs only).
+	Line int
+}
+
+// Layers pairs the DiffIDs of an image config with the history entries that created them.
+func Layers(cfg ImageConfig) []Layer {
+	layers := make([]Layer, len(cfg.RootFS.DiffIDs))
+	for i, diffID := range cfg.RootFS.DiffIDs {
+		layers[i] = Layer{Index: i + 1, DiffID: diffID}
+	}
+
+	i := 0
+	for _, h := range cfg.History {
+		if h.EmptyLayer {
+			continue
+		}
+		if i >= len(layers) {
+			break
+		}
+		layers[i].CreatedBy = h.CreatedBy
+		i++
+	}
+	return layers
+}
+
+// MatchDockerfile sets the Origin and Line of the layers of an image built from the chain of
+// stages (see dockerfile.Chain). Layer-creating instructions are matched from the top of the
+// image down, skipping instructions that do not match the layer's history entry (a RUN that
+// changed nothing has no layer) and layers left by other instructions (WORKDIR); the layers
+// below the last match come from the base image.
+func MatchDockerfile(layers []Layer, chain []dockerfile.Stage) {
+	var instructions []dockerfile.Instruction
+	for _, stage := range chain {
+		for _, in := range stage.Instructions {
+			if in.CreatesLayer() {
+				instructions = append(instructions, in)
+			}
+		}
+	}
+
+	i, j := len(layers)-1, len(instructions)-1
+	for i >= 0 && j >= 0 {
+		// WORKDIR and the like may leave a layer but install nothing: skip it, keep the instruction
+		if kind := layerKind(layers[i].CreatedBy); kind != "" && !(dockerfile.Instruction{Keyword: kind}).CreatesLayer() {
+			i--
+			continue
+		}
+		if !matchesLayer(layers[i].CreatedBy, instructions[j]) {
+			j--
+			continue
+		}
+		layers[i].Origin = OriginAddedByUs
+		layers[i].Line = instructions[j].Line
+		i--
+		j--
+	}
+	for ; i >= 0; i-- {
+		layers[i].Origin = OriginBaseImage
+	}
+}
+
+// matchesLayer reports whether a history entry may have been created by the instruction: same
+// kind and, for shell-form RUN, the same command. Unknown entries match any instruction.
+func matchesLayer(createdBy string, in dockerfile.Instruction) bool {
+	kind := layerKind(createdBy)
+	if kind == "" {
+		return true
+	}
+	if kind != in.Keyword {
+		return false
+	}
+	if kind != "RUN" || strings.HasPrefix(in.Args, "[") {
+		return true
+	}
+
+	// Drop RUN flags (--mount=..., --network=...), which the history records apart from the command
+	fields := strings.Fields(in.Args)
+	for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+		fields = fields[1:]
+	}
+	return strings.Contains(strings.Join(strings.Fields(createdBy), " "), strings.Join(fields, " "))
+}
+
+// historyKeywords are the Dockerfile instructions recognised in history entries.
+var historyKeywords = map[string]bool{
+	"RUN": true, "COPY": true, "ADD": true, "WORKDIR": true, "ENV": true, "LABEL": true,
+	"USER": true, "VOLUME": true, "EXPOSE": true, "CMD": true, "ENTRYPOINT": true, "ARG": true,
+	"SHELL": true, "STOPSIGNAL": true, "HEALTHCHECK": true, "ONBUILD": true, "MAINTAINER": true,
+}
+
+// layerKind returns the instruction (RUN, COPY, WORKDIR, ...) recorded in a history entry, or "" when unknown.
+// BuildKit records the instruction itself ("RUN /bin/sh -c apt-get ... # buildkit"); the legacy
+// builder records the shell command ("/bin/sh -c apt-get ...", "|1 V=1 /bin/sh -c ...") or a
+// no-op marker ("/bin/sh -c #(nop) COPY dir:... in /app").
+func layerKind(createdBy string) string {
+	s := strings.TrimSpace(createdBy)
+	if strings.HasPrefix(s, "|") {
+		return "RUN"
+	}
+	if rest, ok := strings.CutPrefix(s, "/bin/sh -c "); ok {
+		nop, ok := strings.CutPrefix(strings.TrimSpace(rest), "#(nop)")
+		if !ok {
+			return "RUN"
+		}
+		s = strings.TrimSpace(nop)
+	}
+
+	keyword, _, _ := strings.Cut(s, " ")
+	if keyword = strings.ToUpper(keyword); historyKeywords[keyword] {
+		return keyword
+	}
+	return ""
+}
+
+// attributeLayers records the layer of each finding: its index, the history entry that
+// created it and, when matched against a Dockerfile, its origin and Dockerfile line.
+func attributeLayers(findings []model.Finding, layers []Layer) {
+	byDiffID := make(map[string]Layer, len(layers))
+	for _, layer := range layers {
+		byDiffID[layer.DiffID] = layer
+	}
+
+	for i := range findings {
+		f := &findings[i]
+		diffID, _ := f.Metadata["layer_diff_id"].(string)
+		layer, ok := byDiffID[diffID]
+		if !ok || diffID == "" {
+			continue
+		}
+		f.Metadata["layer"] = layer.Index
+		if layer.CreatedBy != "" {
+			f.Metadata["layer_created_by"] = layer.CreatedBy
+		}
+		if layer.Origin != "" {
+			f.Metadata["layer_origin"] = layer.Origin
+		}
+		if layer.Line > 0 {
+			f.Metadata["dockerfile_line"] = layer.Line
+		}
+	}
+}
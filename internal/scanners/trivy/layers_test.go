@@ -0,0 +1,118 @@
+package trivy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"depscanity/internal/dockerfile"
+)
+
+const layersDockerfile = `FROM golang:1.22 AS build
+WORKDIR /src
+COPY . .
+RUN go build -o /out/app .
+
+FROM debian:12-slim
+WORKDIR /app
+RUN apt-get update && apt-get install -y curl
+RUN true
+COPY --from=build /out/app /app/app
+CMD ["/app/app"]
+`
+
+func TestParseImageOutput_Layers(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "trivy_image_layers.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stages, err := dockerfile.Parse([]byte(layersDockerfile), nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	findings, err := parseImageOutput(string(data), dockerfile.Chain(stages, ""))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %d", len(findings))
+	}
+
+	tests := []struct {
+		layer  int
+		origin string
+		line   any
+	}{
+		{1, OriginBaseImage, nil},
+		{4, OriginAddedByUs, 8},
+		{5, OriginAddedByUs, 10},
+	}
+	for i, tt := range tests {
+		f := findings[i]
+		if f.Metadata["layer"] != tt.layer || f.Metadata["layer_origin"] != tt.origin || f.Metadata["dockerfile_line"] != tt.line {
+			t.Errorf("%s: expected layer %d %s line %v, got %v %v %v", f.Package, tt.layer, tt.origin, tt.line,
+				f.Metadata["layer"], f.Metadata["layer_origin"], f.Metadata["dockerfile_line"])
+		}
+	}
+	if findings[1].Metadata["layer_created_by"] != "RUN /bin/sh -c apt-get update && apt-get install -y curl # buildkit" {
+		t.Errorf("unexpected layer_created_by: %v", findings[1].Metadata["layer_created_by"])
+	}
+
+	// Without a Dockerfile, findings keep their layer but get no origin
+	findings, err = ParseTrivyOutput(string(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if findings[0].Metadata["layer"] != 1 || findings[0].Metadata["layer_origin"] != nil {
+		t.Errorf("expected layer 1 without origin, got %v", findings[0].Metadata)
+	}
+}
+
+func TestLayerKind(t *testing.T) {
+	tests := map[string]string{
+		"RUN /bin/sh -c apt-get install -y curl # buildkit": "RUN",
+		"COPY . /app # buildkit":                            "COPY",
+		"/bin/sh -c apt-get update":                         "RUN",
+		"|1 VERSION=1.2 /bin/sh -c make":                    "RUN",
+		"/bin/sh -c #(nop) ADD file:4b1be1de1a1e5aa6 in / ": "ADD",
+		"/bin/sh -c #(nop) COPY dir:8c9f2a in /app ":        "COPY",
+		"":                                  "",
+		"/bin/sh -c #(nop)  CMD [\"bash\"]": "CMD",
+		"WORKDIR /app":                      "WORKDIR",
+		"/bin/sh -c #(nop) WORKDIR /app":    "WORKDIR",
+	}
+	for createdBy, want := range tests {
+		if got := layerKind(createdBy); got != want {
+			t.Errorf("layerKind(%q) = %q, want %q", createdBy, got, want)
+		}
+	}
+}
+
+func TestMatchDockerfile_Workdir(t *testing.T) {
+	stages, err := dockerfile.Parse([]byte("FROM debian:12-slim\nRUN apt-get update && apt-get install -y curl\nWORKDIR /app\nCOPY . .\n"), nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// BuildKit and the legacy builder both leave a layer for WORKDIR
+	for _, workdir := range []string{"WORKDIR /app", "/bin/sh -c #(nop) WORKDIR /app"} {
+		layers := []Layer{
+			{Index: 1, CreatedBy: "/bin/sh -c #(nop) ADD file:4b1be1de1a1e5aa6 in / "},
+			{Index: 2, CreatedBy: "RUN /bin/sh -c apt-get update && apt-get install -y curl # buildkit"},
+			{Index: 3, CreatedBy: workdir},
+			{Index: 4, CreatedBy: "COPY . . # buildkit"},
+		}
+		MatchDockerfile(layers, dockerfile.Chain(stages, ""))
+
+		if layers[0].Origin != OriginBaseImage {
+			t.Errorf("%s: expected the ADD layer from the base image, got %+v", workdir, layers[0])
+		}
+		if layers[1].Origin != OriginAddedByUs || layers[1].Line != 2 {
+			t.Errorf("%s: expected apt-get layer added by line 2, got %+v", workdir, layers[1])
+		}
+		if layers[3].Origin != OriginAddedByUs || layers[3].Line != 4 {
+			t.Errorf("%s: expected COPY layer added by line 4, got %+v", workdir, layers[3])
+		}
+	}
+}
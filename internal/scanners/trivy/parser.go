@@ -3,42 +3,153 @@ package trivy
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
+	"depscanity/internal/dockerfile"
 	"depscanity/internal/model"
 )
 
 type TrivyReport struct {
-	Results []TrivyResult `json:"Results"`
+	Metadata TrivyMetadata `json:"Metadata"`
+	Results  []TrivyResult `json:"Results"`
+}
+
+// TrivyMetadata holds the image config of trivy image reports (empty for fs/config reports).
+type TrivyMetadata struct {
+	ImageConfig ImageConfig `json:"ImageConfig"`
 }
 
 type TrivyResult struct {
-	Target          string               `json:"Target"`
-	Vulnerabilities []TrivyVulnerability `json:"Vulnerabilities"`
+	Target            string                  `json:"Target"`
+	Class             string                  `json:"Class"`
+	Type              string                  `json:"Type"`
+	Vulnerabilities   []TrivyVulnerability    `json:"Vulnerabilities"`
+	Misconfigurations []TrivyMisconfiguration `json:"Misconfigurations"`
 }
 
 type TrivyVulnerability struct {
-	VulnerabilityID  string   `json:"VulnerabilityID"`
-	PkgName          string   `json:"PkgName"`
-	InstalledVersion string   `json:"InstalledVersion"`
-	FixedVersion     string   `json:"FixedVersion"`
-	Title            string   `json:"Title"`
-	Description      string   `json:"Description"`
-	Severity         string   `json:"Severity"`
-	PrimaryURL       string   `json:"PrimaryURL"`
-	References       []string `json:"References"`
+	VulnerabilityID  string `json:"VulnerabilityID"`
+	PkgName          string `json:"PkgName"`
+	PkgID            string `json:"PkgID"`
+	PkgPath          string `json:"PkgPath"`
+	InstalledVersion string `json:"InstalledVersion"`
+	FixedVersion     string `json:"FixedVersion"`
+	// Status is the vendor fix status: fixed, affected, will_not_fix, fix_deferred, end_of_life, ...
+	Status        string               `json:"Status"`
+	Title         string               `json:"Title"`
+	Description   string               `json:"Description"`
+	Severity      string               `json:"Severity"`
+	CweIDs        []string             `json:"CweIDs"`
+	CVSS          map[string]TrivyCVSS `json:"CVSS"`
+	PrimaryURL    string               `json:"PrimaryURL"`
+	References    []string             `json:"References"`
+	PublishedDate string               `json:"PublishedDate"`
+	// Layer is the image layer that installed the package (image scans only).
+	Layer TrivyLayer `json:"Layer"`
+}
+
+// TrivyLayer identifies an image layer by its compressed digest and uncompressed DiffID.
+type TrivyLayer struct {
+	Digest string `json:"Digest"`
+	DiffID string `json:"DiffID"`
+}
+
+// TrivyCVSS holds the CVSS scores of one vendor (nvd, ghsa, redhat, ...).
+type TrivyCVSS struct {
+	V2Vector string  `json:"V2Vector,omitempty"`
+	V3Vector string  `json:"V3Vector,omitempty"`
+	V2Score  float64 `json:"V2Score,omitempty"`
+	V3Score  float64 `json:"V3Score,omitempty"`
+}
+
+// ecosystemByType maps trivy package types to the normalized ecosystems used by the
+// other scanners (the lowercase OSV names), so an npm package inside an image is "npm".
+var ecosystemByType = map[string]string{
+	"npm": "npm", "yarn": "npm", "pnpm": "npm", "bun": "npm", "node-pkg": "npm",
+	"nuget": "nuget", "dotnet-core": "nuget", "packages-props": "nuget",
+	"gomod": "go", "gobinary": "go",
+	"pip": "pypi", "pipenv": "pypi", "poetry": "pypi", "uv": "pypi", "python-pkg": "pypi",
+	"jar": "maven", "pom": "maven", "gradle": "maven", "sbt": "maven",
+	"cargo": "crates.io", "rustbinary": "crates.io", "rust-binary": "crates.io",
+	"composer": "packagist", "composer-vendor": "packagist",
+	"bundler": "rubygems", "gemspec": "rubygems",
+	"pub": "pub", "hex": "hex", "swift": "swifturl", "cocoapods": "cocoapods",
+	"conan": "conan", "conda-pkg": "conda", "conda-environment": "conda",
+	"alma": "almalinux", "cbl-mariner": "mariner", "azurelinux": "azure linux",
+}
+
+// Ecosystem returns the normalized ecosystem of a trivy result.
+// OS package types (alpine, debian, ubuntu, ...) keep their distribution name;
+// results without a type fall back to "container".
+func Ecosystem(resultType string) string {
+	t := strings.ToLower(resultType)
+	if e, ok := ecosystemByType[t]; ok {
+		return e
+	}
+	if t == "" {
+		return "container"
+	}
+	return t
+}
+
+// TrivyMisconfiguration is a failed check reported by trivy config (or --scanners misconfig).
+type TrivyMisconfiguration struct {
+	Type          string   `json:"Type"`
+	ID            string   `json:"ID"`
+	AVDID         string   `json:"AVDID"`
+	Title         string   `json:"Title"`
+	Description   string   `json:"Description"`
+	Message       string   `json:"Message"`
+	Resolution    string   `json:"Resolution"`
+	Severity      string   `json:"Severity"`
+	PrimaryURL    string   `json:"PrimaryURL"`
+	References    []string `json:"References"`
+	Status        string   `json:"Status"`
+	CauseMetadata struct {
+		Resource  string `json:"Resource"`
+		Provider  string `json:"Provider"`
+		Service   string `json:"Service"`
+		StartLine int    `json:"StartLine"`
+		EndLine   int    `json:"EndLine"`
+	} `json:"CauseMetadata"`
 }
 
 func ParseTrivyOutput(jsonOutput string) ([]model.Finding, error) {
+	return parseImageOutput(jsonOutput, nil)
+}
+
+// parseImageOutput converts trivy image output and attributes the findings to image layers;
+// with the Dockerfile stages the image was built from, layers are also marked as coming
+// from the base image or from a Dockerfile line.
+func parseImageOutput(jsonOutput string, chain []dockerfile.Stage) ([]model.Finding, error) {
 	var report TrivyReport
-	if err := json.Unmarshal([]byte(jsonOutput), &report); err != nil {
+	if err := unmarshalReport(jsonOutput, &report); err != nil {
+		return nil, err
+	}
+	findings := convertVulnerabilities(report.Results)
+
+	layers := Layers(report.Metadata.ImageConfig)
+	if chain != nil {
+		MatchDockerfile(layers, chain)
+	}
+	attributeLayers(findings, layers)
+	return findings, nil
+}
+
+func unmarshalReport(jsonOutput string, report *TrivyReport) error {
+	if err := json.Unmarshal([]byte(jsonOutput), report); err != nil {
 		// Sometimes trivy outputs nothing if no vulnerabilities? Or empty JSON?
 		// Ensure it's valid JSON
-		return nil, fmt.Errorf("failed to unmarshal trivy json: %w", err)
+		return fmt.Errorf("failed to unmarshal trivy json: %w", err)
 	}
+	return nil
+}
 
+// convertVulnerabilities normalizes the vulnerabilities of trivy results into findings.
+func convertVulnerabilities(results []TrivyResult) []model.Finding {
 	var findings []model.Finding
 
-	for _, result := range report.Results {
+	for _, result := range results {
 		for _, v := range result.Vulnerabilities {
 			// Normalize fields
 
@@ -75,7 +186,7 @@ func ParseTrivyOutput(jsonOutput string) ([]model.Finding, error) {
 
 			f := model.Finding{
 				Source:           "trivy",
-				Ecosystem:        "container", // Default to container for now
+				Ecosystem:        Ecosystem(result.Type),
 				Package:          v.PkgName,
 				InstalledVersion: v.InstalledVersion,
 				FixedVersion:     fixedPtr,
@@ -86,11 +197,102 @@ func ParseTrivyOutput(jsonOutput string) ([]model.Finding, error) {
 				Location:         result.Target,
 				Metadata: map[string]any{
 					"description": v.Description,
+					"class":       result.Class,
+					"type":        result.Type,
 				},
 			}
+			// In-image location and identity of the package (PkgPath is set for language packages)
+			if v.PkgPath != "" {
+				f.Metadata["pkg_path"] = v.PkgPath
+			}
+			if v.PkgID != "" {
+				f.Metadata["pkg_id"] = v.PkgID
+			}
+			if v.Status != "" {
+				f.Metadata["status"] = v.Status
+			}
+			if len(v.CweIDs) > 0 {
+				f.Metadata["cwe_ids"] = v.CweIDs
+			}
+			if len(v.CVSS) > 0 {
+				f.Metadata["cvss"] = v.CVSS
+			}
+			if v.PublishedDate != "" {
+				f.Metadata["published_date"] = v.PublishedDate
+			}
+			if v.Layer.DiffID != "" {
+				f.Metadata["layer_diff_id"] = v.Layer.DiffID
+			}
+			if v.Layer.Digest != "" {
+				f.Metadata["layer_digest"] = v.Layer.Digest
+			}
 			findings = append(findings, f)
 		}
 	}
 
+	return findings
+}
+
+// ParseTrivyMisconfigurations converts the failed checks of trivy JSON output into
+// misconfiguration findings. The check ID is the VulnerabilityID, the file is the Location
+// and the affected resource (if any) is the Package.
+func ParseTrivyMisconfigurations(jsonOutput string) ([]model.Finding, error) {
+	var report TrivyReport
+	if err := unmarshalReport(jsonOutput, &report); err != nil {
+		return nil, err
+	}
+
+	var findings []model.Finding
+	for _, result := range report.Results {
+		for _, m := range result.Misconfigurations {
+			if m.Status != "" && m.Status != "FAIL" {
+				continue
+			}
+
+			sev, _ := model.ParseSeverity(m.Severity)
+
+			url := m.PrimaryURL
+			if url == "" && len(m.References) > 0 {
+				url = m.References[0]
+			}
+			var urlPtr *string
+			if url != "" {
+				urlPtr = &url
+			}
+
+			title := m.Title
+			if title == "" {
+				title = m.ID
+			}
+
+			metadata := map[string]any{
+				"check_type":  m.Type,
+				"message":     m.Message,
+				"resolution":  m.Resolution,
+				"description": m.Description,
+			}
+			if m.AVDID != "" {
+				metadata["avd_id"] = m.AVDID
+			}
+			if m.CauseMetadata.StartLine > 0 {
+				metadata["start_line"] = m.CauseMetadata.StartLine
+				metadata["end_line"] = m.CauseMetadata.EndLine
+			}
+
+			findings = append(findings, model.Finding{
+				Source:          "trivy",
+				Ecosystem:       strings.ToLower(result.Type),
+				Package:         m.CauseMetadata.Resource,
+				VulnerabilityID: m.ID,
+				Severity:        sev,
+				Title:           &title,
+				URL:             urlPtr,
+				Location:        result.Target,
+				Class:           model.ClassMisconfiguration,
+				Metadata:        metadata,
+			})
+		}
+	}
+
 	return findings, nil
 }
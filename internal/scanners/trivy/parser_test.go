@@ -48,3 +48,108 @@ func TestParseTrivyOutput(t *testing.T) {
 		t.Errorf("expected fallback URL check failed")
 	}
 }
+
+func TestParseTrivyMisconfigurations(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "trivy_config_output.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParseTrivyMisconfigurations(string(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 failed checks (PASS skipped), got %d", len(findings))
+	}
+
+	ds := findings[0]
+	if ds.Class != model.ClassMisconfiguration || ds.IsVulnerability() {
+		t.Errorf("expected misconfiguration class, got %q", ds.Class)
+	}
+	if ds.VulnerabilityID != "DS002" || ds.Ecosystem != "dockerfile" || ds.Location != "Dockerfile" || ds.Severity != model.SeverityHigh {
+		t.Errorf("unexpected Dockerfile finding: %s %s %s %s", ds.VulnerabilityID, ds.Ecosystem, ds.Location, ds.Severity)
+	}
+
+	k8s := findings[1]
+	if k8s.Package != "Deployment/api" || k8s.Metadata["start_line"] != 21 {
+		t.Errorf("expected resource and line, got %s %v", k8s.Package, k8s.Metadata)
+	}
+}
+
+func TestNonNativeResults(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "trivy_config_output.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var report TrivyReport
+	if err := unmarshalReport(string(data), &report); err != nil {
+		t.Fatal(err)
+	}
+	findings := convertVulnerabilities(nonNativeResults(report.Results))
+	if len(findings) != 1 || findings[0].Package != "http" {
+		t.Errorf("expected only the pub finding (npm is scanned natively), got %+v", findings)
+	}
+}
+
+func TestParseTrivyOutput_Ecosystems(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "trivy_image_app.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParseTrivyOutput(string(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(findings) != 5 {
+		t.Fatalf("expected 5 findings, got %d", len(findings))
+	}
+
+	want := []string{"debian", "debian", "npm", "go", "maven"}
+	for i, f := range findings {
+		if f.Ecosystem != want[i] {
+			t.Errorf("finding %d (%s): expected ecosystem %s, got %s", i, f.Package, want[i], f.Ecosystem)
+		}
+	}
+
+	glibc := findings[0]
+	if glibc.Metadata["pkg_id"] != "libc6@2.36-9+deb12u4" || glibc.Metadata["status"] != "fixed" {
+		t.Errorf("expected pkg_id and status, got %v", glibc.Metadata)
+	}
+	if glibc.Metadata["published_date"] != "2024-04-17T18:15:15.833Z" {
+		t.Errorf("expected published date, got %v", glibc.Metadata["published_date"])
+	}
+	cvss, ok := glibc.Metadata["cvss"].(map[string]TrivyCVSS)
+	if !ok || cvss["nvd"].V3Score != 8.1 {
+		t.Errorf("expected nvd CVSS score, got %v", glibc.Metadata["cvss"])
+	}
+	if cwes, ok := glibc.Metadata["cwe_ids"].([]string); !ok || len(cwes) != 1 || cwes[0] != "CWE-787" {
+		t.Errorf("expected CWE ids, got %v", glibc.Metadata["cwe_ids"])
+	}
+	if findings[1].Metadata["status"] != "will_not_fix" {
+		t.Errorf("expected will_not_fix status, got %v", findings[1].Metadata["status"])
+	}
+
+	semver := findings[2]
+	if semver.Metadata["pkg_path"] != "app/node_modules/semver/package.json" || semver.Location != "Node.js" {
+		t.Errorf("expected in-image package path, got %v (%s)", semver.Metadata["pkg_path"], semver.Location)
+	}
+}
+
+func TestReadImagesFile(t *testing.T) {
+	images, err := ReadImagesFile(filepath.Join("testdata", "images.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"ghcr.io/acme/api:1.4.2", "ghcr.io/acme/worker:1.4.2", "ghcr.io/acme/migrations@sha256:3f1a9c0d"}
+	if len(images) != len(want) {
+		t.Fatalf("expected %v, got %v", want, images)
+	}
+	for i := range want {
+		if images[i] != want[i] {
+			t.Errorf("image %d: expected %s, got %s", i, want[i], images[i])
+		}
+	}
+}
@@ -7,14 +7,160 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
-	depExec "depscanity/internal/exec"
+	"depscanity/internal/detect"
+	"depscanity/internal/dockerfile"
 	"depscanity/internal/model"
 	"depscanity/internal/report"
+	"depscanity/internal/scanners"
 )
 
+// LocalImageTag is the tag used for images built by --docker-build.
+const LocalImageTag = "depscanity:local"
+
+// targetKind tells Scan what a target is, as decided by Targets.
+type targetKind int
+
+const (
+	kindImage targetKind = iota
+	kindDockerfile
+	// kindArchive is a docker save / OCI tarball or an OCI layout directory.
+	kindArchive
+)
+
+// Scanner scans container images with trivy image.
+type Scanner struct {
+	opts scanners.Options
+	// kinds and tags hold the kind of each target and the build tag of each Dockerfile target, set by Targets.
+	kinds map[string]targetKind
+	tags  map[string]string
+}
+
+// New returns the trivy scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "trivy" }
+
+// LockGroup leaves Dockerfile targets unlocked: their build runs alongside other scans and
+// ScanBuiltImage holds the group only for the trivy scan of the result.
+func (s *Scanner) LockGroup(target string) string {
+	if s.kinds[target] == kindDockerfile {
+		return ""
+	}
+	return LockGroup
+}
+
+// Targets returns the images given with --image / --images-file or, with --docker-build, every
+// detected Dockerfile (only --dockerfile when given), followed by the image archives
+// (--image-archive, --oci-layout and detected *.tar image tarballs).
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	if s.opts.NoContainer {
+		return nil
+	}
+
+	s.kinds = make(map[string]targetKind)
+	var targets, dockerfiles []string
+	switch {
+	case len(s.opts.Images) > 0:
+		targets = append(targets, s.opts.Images...)
+	case s.opts.Dockerfile != "":
+		// Any name is accepted here (Containerfile, build/app.df, ...)
+		dockerfiles = append(dockerfiles, s.opts.Dockerfile)
+	case s.opts.DockerBuild:
+		for _, path := range det.Docker {
+			if detect.IsDockerfile(path) {
+				dockerfiles = append(dockerfiles, path)
+			}
+		}
+	}
+	for _, path := range dockerfiles {
+		s.kinds[path] = kindDockerfile
+	}
+	s.tags = BuildTags(s.opts.Root, dockerfiles)
+	targets = append(targets, dockerfiles...)
+
+	seen := make(map[string]bool)
+	archives := append([]string{s.opts.ImageArchive, s.opts.OCILayout}, det.ImageArchives...)
+	for _, archive := range archives {
+		if archive == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(archive); err == nil {
+			archive = abs
+		}
+		if !seen[archive] {
+			seen[archive] = true
+			s.kinds[archive] = kindArchive
+			targets = append(targets, archive)
+		}
+	}
+	return targets
+}
+
+// Scan builds Dockerfile targets before scanning them and scans image archives and OCI
+// layouts directly. Any other target is an image reference, even if a local path has its name.
+func (s *Scanner) Scan(ctx context.Context, target string) ([]model.Finding, []report.ScannerError) {
+	switch s.kinds[target] {
+	case kindDockerfile:
+		tag, ok := s.tags[target]
+		if !ok {
+			tag = BuildTag(s.opts.Root, target)
+		}
+		return ScanBuiltImage(ctx, s.buildSpec(target), tag, s.opts)
+	case kindArchive:
+		if info, err := os.Stat(target); err == nil && info.IsDir() && !detect.IsOCILayout(target) {
+			return nil, []report.ScannerError{{
+				Source:   "trivy",
+				Location: target,
+				Message:  "directory is not an OCI image layout (no oci-layout file)",
+			}}
+		}
+		return ScanTrivyInput(ctx, target, s.opts)
+	default:
+		return ScanTrivy(ctx, target, s.opts)
+	}
+}
+
+// buildSpec applies the build options to a Dockerfile. The context defaults to the Dockerfile's directory.
+func (s *Scanner) buildSpec(dockerfile string) BuildSpec {
+	spec := BuildSpec{
+		Context:    filepath.Dir(dockerfile),
+		Dockerfile: dockerfile,
+		Target:     s.opts.BuildTarget,
+		Platform:   s.opts.Platform,
+		Args:       s.opts.BuildArgs,
+	}
+	if s.opts.BuildContext != "" {
+		spec.Context = s.opts.BuildContext
+	}
+	return spec
+}
+
 // ScanTrivy executes trivy image and parses the results.
-func ScanTrivy(ctx context.Context, imageRef string, timeoutSec int, outDir string) ([]model.Finding, []report.ScannerError) {
+func ScanTrivy(ctx context.Context, imageRef string, opts scanners.Options) ([]model.Finding, []report.ScannerError) {
+	return scanImage(ctx, imageRef, []string{imageRef}, nil, opts)
+}
+
+// ScanTrivyInput scans a docker save / OCI tarball or an OCI layout directory with
+// trivy image --input, which needs no Docker daemon or registry. Findings are attributed
+// to the archive: it becomes the Location, and trivy's own target is kept in metadata.
+func ScanTrivyInput(ctx context.Context, inputPath string, opts scanners.Options) ([]model.Finding, []report.ScannerError) {
+	findings, scannerErrors := scanImage(ctx, inputPath, []string{"--input", inputPath}, nil, opts)
+	for i := range findings {
+		f := &findings[i]
+		f.Metadata["target"] = f.Location
+		f.Metadata["image_archive"] = inputPath
+		f.Location = inputPath
+	}
+	return findings, scannerErrors
+}
+
+// scanImage runs trivy image with the image selection args and parses the results.
+// chain holds the Dockerfile stages of images built by DepScanity (nil otherwise).
+func scanImage(ctx context.Context, imageRef string, imageArgs []string, chain []dockerfile.Stage, opts scanners.Options) ([]model.Finding, []report.ScannerError) {
 	var findings []model.Finding
 	var scannerErrors []report.ScannerError
 
@@ -28,7 +174,16 @@ func ScanTrivy(ctx context.Context, imageRef string, timeoutSec int, outDir stri
 		return findings, scannerErrors
 	}
 
-	rawOutDir := filepath.Join(outDir, "raw")
+	if err := CheckDB(opts); err != nil {
+		scannerErrors = append(scannerErrors, report.ScannerError{
+			Source:   "trivy",
+			Location: imageRef,
+			Message:  err.Error(),
+		})
+		return findings, scannerErrors
+	}
+
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
 	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
 		scannerErrors = append(scannerErrors, report.ScannerError{
 			Source:   "trivy",
@@ -39,18 +194,27 @@ func ScanTrivy(ctx context.Context, imageRef string, timeoutSec int, outDir stri
 	}
 
 	// 2. Run Trivy
-	// trivy image --format json --no-progress <imageRef>
-	args := []string{"image", "--format", "json", "--no-progress", imageRef}
+	// trivy image --format json --no-progress [db flags] <imageRef> (or --input <archive>)
+	args := append([]string{"image", "--format", "json", "--no-progress"}, DBArgs(opts)...)
+	args = append(args, imageArgs...)
 
 	// We run it with a timeout context
-	res, err := depExec.Run(ctx, "trivy", args, ".")
+	res, err := opts.RunPhase(ctx, "trivy", scanners.PhaseAudit, imageRef, "trivy", args, ".")
 
 	// Save runner output (stdout/stderr) for debugging
 	sanitized := sanitizePath(imageRef)
 	runFile := filepath.Join(rawOutDir, fmt.Sprintf("trivy-run-%s.txt", sanitized))
 	_ = os.WriteFile(runFile, []byte(fmt.Sprintf("STDOUT:\n%s\nSTDERR:\n%s\nEXIT: %d\nERROR: %v", res.Stdout, res.Stderr, res.ExitCode, err)), 0644)
 
-	if res.ExitCode == 127 || res.ExitCode == 124 {
+	if res.ExitCode == 124 {
+		scannerErrors = append(scannerErrors, report.ScannerError{
+			Source:   "trivy",
+			Location: imageRef,
+			Message:  fmt.Sprintf("trivy image timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit)),
+		})
+		return findings, scannerErrors
+	}
+	if res.ExitCode == 127 {
 		scannerErrors = append(scannerErrors, report.ScannerError{
 			Source:   "trivy",
 			Location: imageRef,
@@ -59,12 +223,12 @@ func ScanTrivy(ctx context.Context, imageRef string, timeoutSec int, outDir stri
 		return findings, scannerErrors
 	}
 
-	// 3. Save raw JSON
+	// 3. Save raw JSON (one file per image)
 	jsonFile := filepath.Join(rawOutDir, fmt.Sprintf("trivy-image-%s.json", sanitized))
 	_ = os.WriteFile(jsonFile, []byte(res.Stdout), 0644)
 
 	// 4. Parse
-	findings, err = ParseTrivyOutput(res.Stdout)
+	findings, err = parseImageOutput(res.Stdout, chain)
 	if err != nil {
 		scannerErrors = append(scannerErrors, report.ScannerError{
 			Source:   "trivy",
@@ -72,10 +236,32 @@ func ScanTrivy(ctx context.Context, imageRef string, timeoutSec int, outDir stri
 			Message:  fmt.Sprintf("parse error: %v", err),
 		})
 	}
+	for i := range findings {
+		findings[i].Metadata["image"] = imageRef
+	}
 
 	return findings, scannerErrors
 }
 
+// ReadImagesFile reads image references, one per line. Blank lines and # comments are ignored.
+func ReadImagesFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			images = append(images, line)
+		}
+	}
+	return images, nil
+}
+
 func sanitizePath(path string) string {
 	s := strings.Map(func(r rune) rune {
 		if r == '/' || r == '\\' || r == ':' || r == ' ' {
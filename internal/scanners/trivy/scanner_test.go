@@ -0,0 +1,46 @@
+package trivy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/scanners"
+)
+
+func TestScan_ContainerfileIsBuilt(t *testing.T) {
+	root := t.TempDir()
+	containerfile := filepath.Join(root, "build", "Containerfile")
+	os.MkdirAll(filepath.Dir(containerfile), 0755)
+	os.WriteFile(containerfile, []byte("FROM alpine:3.19\n"), 0644)
+	// No builder on PATH: the scan stops at the build, which proves the target was built
+	t.Setenv("PATH", "")
+
+	s := &Scanner{opts: scanners.Options{Root: root, OutDir: t.TempDir(), Dockerfile: containerfile}}
+	targets := s.Targets(detect.DetectionResult{})
+	if len(targets) != 1 || targets[0] != containerfile {
+		t.Fatalf("expected the Containerfile as only target, got %v", targets)
+	}
+	if group := s.LockGroup(containerfile); group != "" {
+		t.Errorf("expected the build target to be unlocked, got group %q", group)
+	}
+
+	_, errs := s.Scan(context.Background(), containerfile)
+	if len(errs) != 1 || errs[0].Source != "trivy-build" {
+		t.Errorf("expected a build error, got %+v", errs)
+	}
+}
+
+func TestTargets_ImageNamedLikeLocalPath(t *testing.T) {
+	root := t.TempDir()
+	image := filepath.Join(root, "app")
+	os.WriteFile(image, []byte("not an image"), 0644)
+
+	s := &Scanner{opts: scanners.Options{Root: root, Images: []string{image}}}
+	s.Targets(detect.DetectionResult{})
+	if s.kinds[image] != kindImage || s.LockGroup(image) != LockGroup {
+		t.Errorf("expected --image values to stay image references, got kind %d", s.kinds[image])
+	}
+}
@@ -0,0 +1,93 @@
+package yarn
+
+import (
+	"bufio"
+	"os"
+	"sort"
+	"strings"
+)
+
+// parseYarnLock parses yarn.lock (classic v1 or Berry) and maps package names to installed versions.
+//
+// Classic:
+//
+//	"minimist@^1.2.0", minimist@^1.2.5:
+//	  version "1.2.5"
+//
+// Berry:
+//
+//	"minimist@npm:^1.2.0, minimist@npm:^1.2.5":
+//	  version: 1.2.5
+func parseYarnLock(lockPath string) (map[string][]string, error) {
+	file, err := os.Open(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	versions := make(map[string]map[string]bool)
+	var currentName string
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// Entry header: unindented and ending with ':'
+		if !strings.HasPrefix(line, " ") && strings.HasSuffix(trimmed, ":") {
+			currentName = entryName(strings.TrimSuffix(trimmed, ":"))
+			continue
+		}
+
+		if currentName == "" || !strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "   ") {
+			continue
+		}
+
+		// "  version \"1.2.5\"" (classic) or "  version: 1.2.5" (berry)
+		if strings.HasPrefix(trimmed, "version ") || strings.HasPrefix(trimmed, "version:") {
+			v := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(trimmed, "version"), ":"))
+			v = strings.Trim(v, "\"")
+			if v == "" || v == "0.0.0-use.local" {
+				continue
+			}
+			if versions[currentName] == nil {
+				versions[currentName] = make(map[string]bool)
+			}
+			versions[currentName][v] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]string, len(versions))
+	for name, set := range versions {
+		for v := range set {
+			result[name] = append(result[name], v)
+		}
+		sort.Strings(result[name])
+	}
+	return result, nil
+}
+
+// entryName extracts the package name from an entry header such as
+// `"@babel/core@^7.0.0", "@babel/core@^7.1.0"` or `"lodash@npm:^4.17.0"`.
+// Workspace entries and __metadata return an empty name.
+func entryName(header string) string {
+	first := strings.TrimSpace(strings.Split(header, ",")[0])
+	first = strings.Trim(first, "\"")
+	if first == "__metadata" || strings.Contains(first, "@workspace:") {
+		return ""
+	}
+
+	// The version separator is the first '@' after the (optional) scope prefix
+	idx := strings.Index(first[1:], "@")
+	if idx < 0 {
+		return first
+	}
+	return first[:idx+1]
+}
@@ -0,0 +1,252 @@
+package yarn
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"depscanity/internal/model"
+)
+
+// Advisory is the npm registry advisory embedded in yarn v1 and Yarn 3 audit output.
+type Advisory struct {
+	ID                 int      `json:"id"`
+	ModuleName         string   `json:"module_name"`
+	Title              string   `json:"title"`
+	URL                string   `json:"url"`
+	Severity           string   `json:"severity"`
+	VulnerableVersions string   `json:"vulnerable_versions"`
+	PatchedVersions    string   `json:"patched_versions"`
+	GithubAdvisoryID   string   `json:"github_advisory_id"`
+	CVEs               []string `json:"cves"`
+	Findings           []struct {
+		Version string   `json:"version"`
+		Paths   []string `json:"paths"`
+	} `json:"findings"`
+}
+
+// auditLine is one NDJSON line of `yarn audit --json` (v1) or `yarn npm audit --json` (Yarn 4).
+type auditLine struct {
+	// v1
+	Type string `json:"type"`
+	Data struct {
+		Advisory Advisory `json:"advisory"`
+	} `json:"data"`
+
+	// Yarn 3: a single npm v6 style document
+	Advisories map[string]Advisory `json:"advisories"`
+
+	// Yarn 4
+	Value    string `json:"value"`
+	Children struct {
+		ID                 any      `json:"ID"`
+		Issue              string   `json:"Issue"`
+		URL                string   `json:"URL"`
+		Severity           string   `json:"Severity"`
+		VulnerableVersions string   `json:"Vulnerable Versions"`
+		TreeVersions       []string `json:"Tree Versions"`
+		Dependents         []string `json:"Dependents"`
+	} `json:"children"`
+}
+
+var (
+	ghsaRegex    = regexp.MustCompile(`GHSA-[0-9a-z]{4}-[0-9a-z]{4}-[0-9a-z]{4}`)
+	patchedRegex = regexp.MustCompile(`^>=\s*([0-9][0-9A-Za-z.\-+]*)$`)
+)
+
+// ParseYarnAudit parses the output of `yarn audit --json` (classic) or `yarn npm audit --json` (Berry)
+// and fills installed versions from yarn.lock when the audit output does not carry them.
+func ParseYarnAudit(output string, lockPath string) ([]model.Finding, error) {
+	var findings []model.Finding
+	if strings.TrimSpace(output) == "" {
+		return findings, nil
+	}
+
+	installedMap, err := parseYarnLock(lockPath)
+	if err != nil {
+		fmt.Printf("Warning: failed to parse yarn.lock: %v\n", err)
+	}
+
+	seen := make(map[string]bool)
+	add := func(f model.Finding) {
+		key := f.Package + "|" + f.InstalledVersion + "|" + f.VulnerabilityID
+		if !seen[key] {
+			seen[key] = true
+			findings = append(findings, f)
+		}
+	}
+
+	parsed := 0
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var al auditLine
+		if err := json.Unmarshal([]byte(line), &al); err != nil {
+			continue
+		}
+		parsed++
+
+		switch {
+		case al.Type == "auditAdvisory":
+			for _, f := range advisoryFindings(al.Data.Advisory, installedMap, lockPath) {
+				add(f)
+			}
+		case al.Advisories != nil:
+			for _, adv := range al.Advisories {
+				for _, f := range advisoryFindings(adv, installedMap, lockPath) {
+					add(f)
+				}
+			}
+		case al.Value != "":
+			for _, f := range berryFindings(al, installedMap, lockPath) {
+				add(f)
+			}
+		}
+	}
+
+	if parsed == 0 {
+		// Yarn 3 pretty-prints a single document over multiple lines
+		var al auditLine
+		if err := json.Unmarshal([]byte(output), &al); err != nil {
+			return nil, fmt.Errorf("failed to parse yarn audit json: %w", err)
+		}
+		for _, adv := range al.Advisories {
+			for _, f := range advisoryFindings(adv, installedMap, lockPath) {
+				add(f)
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+func advisoryFindings(adv Advisory, installedMap map[string][]string, lockPath string) []model.Finding {
+	sev, err := model.ParseSeverity(adv.Severity)
+	if err != nil {
+		sev = model.SeverityUnknown
+	}
+
+	vulnID := vulnerabilityID(adv.GithubAdvisoryID, adv.URL, adv.ID)
+	fixed := fixedVersion(adv.PatchedVersions)
+
+	versions := make(map[string][]string)
+	for _, f := range adv.Findings {
+		versions[f.Version] = append(versions[f.Version], f.Paths...)
+	}
+	if len(versions) == 0 {
+		for _, v := range installedVersions(installedMap, adv.ModuleName) {
+			versions[v] = nil
+		}
+	}
+
+	ordered := make([]string, 0, len(versions))
+	for v := range versions {
+		ordered = append(ordered, v)
+	}
+	sort.Strings(ordered)
+
+	var findings []model.Finding
+	for _, version := range ordered {
+		paths := versions[version]
+		title := adv.Title
+		url := adv.URL
+		findings = append(findings, model.Finding{
+			Source:           "yarn",
+			Ecosystem:        "npm",
+			Package:          adv.ModuleName,
+			InstalledVersion: version,
+			FixedVersion:     fixed,
+			VulnerabilityID:  vulnID,
+			Severity:         sev,
+			Title:            &title,
+			URL:              &url,
+			Location:         lockPath,
+			Metadata: map[string]any{
+				"vulnerable_versions": adv.VulnerableVersions,
+				"patched_versions":    adv.PatchedVersions,
+				"cves":                adv.CVEs,
+				"paths":               paths,
+			},
+		})
+	}
+	return findings
+}
+
+func berryFindings(al auditLine, installedMap map[string][]string, lockPath string) []model.Finding {
+	c := al.Children
+	sev, err := model.ParseSeverity(c.Severity)
+	if err != nil {
+		sev = model.SeverityUnknown
+	}
+
+	var numericID int
+	if id, ok := c.ID.(float64); ok {
+		numericID = int(id)
+	}
+	vulnID := vulnerabilityID("", c.URL, numericID)
+
+	versions := c.TreeVersions
+	if len(versions) == 0 {
+		versions = installedVersions(installedMap, al.Value)
+	}
+
+	var findings []model.Finding
+	for _, version := range versions {
+		title := c.Issue
+		url := c.URL
+		findings = append(findings, model.Finding{
+			Source:           "yarn",
+			Ecosystem:        "npm",
+			Package:          al.Value,
+			InstalledVersion: version,
+			VulnerabilityID:  vulnID,
+			Severity:         sev,
+			Title:            &title,
+			URL:              &url,
+			Location:         lockPath,
+			Metadata: map[string]any{
+				"vulnerable_versions": c.VulnerableVersions,
+				"dependents":          c.Dependents,
+			},
+		})
+	}
+	return findings
+}
+
+func installedVersions(installedMap map[string][]string, pkgName string) []string {
+	if v, ok := installedMap[pkgName]; ok && len(v) > 0 {
+		return v
+	}
+	return []string{"unknown"}
+}
+
+// vulnerabilityID prefers the GHSA identifier (explicit or from the advisory URL) over the npm advisory number.
+func vulnerabilityID(ghsa string, url string, id int) string {
+	if ghsa != "" {
+		return ghsa
+	}
+	if m := ghsaRegex.FindString(url); m != "" {
+		return m
+	}
+	if id != 0 {
+		return fmt.Sprintf("NPM-%d", id)
+	}
+	return "NPM-Unknown"
+}
+
+// fixedVersion turns a simple ">=x.y.z" patched range into a version.
+func fixedVersion(patched string) *string {
+	m := patchedRegex.FindStringSubmatch(strings.TrimSpace(patched))
+	if m == nil {
+		return nil
+	}
+	return &m[1]
+}
@@ -0,0 +1,112 @@
+package yarn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"depscanity/internal/model"
+)
+
+func TestParseYarnLock(t *testing.T) {
+	for _, name := range []string{"yarn_v1.lock", "yarn_berry.lock"} {
+		versions, err := parseYarnLock(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatalf("%s: parse failed: %v", name, err)
+		}
+		if v := versions["@babel/code-frame"]; len(v) != 1 || v[0] != "7.12.13" {
+			t.Errorf("%s: expected scoped package 7.12.13, got %v", name, v)
+		}
+		if _, ok := versions["app"]; ok {
+			t.Errorf("%s: workspace entry should be skipped", name)
+		}
+	}
+
+	versions, _ := parseYarnLock(filepath.Join("testdata", "yarn_v1.lock"))
+	if v := versions["minimist"]; len(v) != 2 || v[0] != "0.0.8" || v[1] != "1.2.5" {
+		t.Errorf("expected both minimist versions, got %v", v)
+	}
+}
+
+func TestParseYarnAudit_Classic(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "yarn_audit_v1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParseYarnAudit(string(data), filepath.Join("testdata", "yarn_v1.lock"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// GHSA-vh95 on 0.0.8 (reported twice, once per path) + NPM-1084 on 1.2.5 and 0.0.8
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %d", len(findings))
+	}
+
+	f := findings[0]
+	if f.Source != "yarn" || f.Ecosystem != "npm" {
+		t.Errorf("unexpected source/ecosystem: %s/%s", f.Source, f.Ecosystem)
+	}
+	if f.VulnerabilityID != "GHSA-vh95-rmgr-6w4m" || f.Severity != model.SeverityMedium {
+		t.Errorf("unexpected first finding: %s %s", f.VulnerabilityID, f.Severity)
+	}
+	if f.FixedVersion == nil || *f.FixedVersion != "1.2.3" {
+		t.Errorf("expected fixed version 1.2.3, got %v", f.FixedVersion)
+	}
+
+	if findings[1].VulnerabilityID != "NPM-1084" || findings[1].InstalledVersion != "0.0.8" {
+		t.Errorf("expected NPM-1084 on 0.0.8, got %s on %s", findings[1].VulnerabilityID, findings[1].InstalledVersion)
+	}
+	if findings[2].InstalledVersion != "1.2.5" || findings[2].Severity != model.SeverityCritical {
+		t.Errorf("expected critical finding on 1.2.5, got %s %s", findings[2].InstalledVersion, findings[2].Severity)
+	}
+}
+
+func TestParseYarnAudit_Berry(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "yarn_audit_berry.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := ParseYarnAudit(string(data), filepath.Join("testdata", "yarn_berry.lock"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+
+	if findings[0].VulnerabilityID != "GHSA-xvch-5gv4-984h" || findings[0].InstalledVersion != "1.2.5" {
+		t.Errorf("unexpected minimist finding: %s %s", findings[0].VulnerabilityID, findings[0].InstalledVersion)
+	}
+	// No "Tree Versions": installed version comes from yarn.lock
+	if findings[1].Package != "@babel/code-frame" || findings[1].InstalledVersion != "7.12.13" {
+		t.Errorf("expected lockfile version for @babel/code-frame, got %s %s", findings[1].Package, findings[1].InstalledVersion)
+	}
+}
+
+func TestParseYarnAudit_Yarn3Document(t *testing.T) {
+	doc := `{
+  "advisories": {
+    "1084": {
+      "id": 1084,
+      "module_name": "minimist",
+      "title": "Prototype Pollution",
+      "url": "https://github.com/advisories/GHSA-xvch-5gv4-984h",
+      "severity": "critical",
+      "vulnerable_versions": "<1.2.6",
+      "patched_versions": ">=1.2.6",
+      "findings": [{"version": "1.2.5", "paths": ["minimist"]}]
+    }
+  },
+  "metadata": {"vulnerabilities": {"critical": 1}}
+}`
+	findings, err := ParseYarnAudit(doc, filepath.Join("testdata", "yarn_berry.lock"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(findings) != 1 || findings[0].VulnerabilityID != "GHSA-xvch-5gv4-984h" {
+		t.Errorf("unexpected findings: %+v", findings)
+	}
+}
@@ -0,0 +1,110 @@
+package yarn
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"depscanity/internal/detect"
+	"depscanity/internal/model"
+	"depscanity/internal/report"
+	"depscanity/internal/scanners"
+)
+
+// MaxLockfiles caps how many yarn.lock files are audited per run.
+const MaxLockfiles = 10
+
+// Scanner audits yarn.lock files with yarn audit (classic) or yarn npm audit (Berry).
+type Scanner struct {
+	opts scanners.Options
+}
+
+// New returns the yarn scanner.
+func New(opts scanners.Options) scanners.Scanner {
+	return &Scanner{opts: opts}
+}
+
+func (s *Scanner) Name() string { return "yarn" }
+
+func (s *Scanner) MaxTargets() int { return MaxLockfiles }
+
+func (s *Scanner) Targets(det detect.DetectionResult) []string {
+	return det.Yarn
+}
+
+func (s *Scanner) Scan(ctx context.Context, lockPath string) ([]model.Finding, []report.ScannerError) {
+	findings, err := ScanYarn(ctx, lockPath, s.opts)
+	if err != nil {
+		return nil, []report.ScannerError{{
+			Source:   "yarn",
+			Location: lockPath,
+			Message:  err.Error(),
+		}}
+	}
+	return findings, nil
+}
+
+// ScanYarn executes the audit command matching the lockfile flavor and parses the results.
+func ScanYarn(ctx context.Context, lockPath string, opts scanners.Options) ([]model.Finding, error) {
+	// 1. Setup paths
+	workDir := filepath.Dir(lockPath)
+	rawOutDir := filepath.Join(opts.OutDir, "raw")
+	if err := os.MkdirAll(rawOutDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create raw output dir: %w", err)
+	}
+
+	// 2. Check yarn existence
+	if _, err := exec.LookPath("yarn"); err != nil {
+		return nil, fmt.Errorf("yarn executable not found in PATH")
+	}
+
+	// 3. Execution
+	// Classic: yarn audit --json (NDJSON)
+	// Berry:   yarn npm audit --json (Yarn 3 document or Yarn 4 NDJSON)
+	berry, err := detect.IsYarnBerry(lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read yarn.lock: %w", err)
+	}
+	args := []string{"audit", "--json"}
+	if berry {
+		args = []string{"npm", "audit", "--all", "--recursive", "--json"}
+	}
+
+	res, err := opts.RunPhase(ctx, "yarn", scanners.PhaseAudit, lockPath, "yarn", args, workDir)
+	// yarn exits non-zero when vulnerabilities are found
+	if res.ExitCode == 124 {
+		return nil, fmt.Errorf("yarn audit timed out after %s (%s)", res.Duration.Round(time.Second), scanners.TimeoutCause(ctx, scanners.PhaseAudit))
+	}
+	if res.ExitCode == 127 {
+		return nil, fmt.Errorf("yarn audit failed execution (code %d): %v", res.ExitCode, err)
+	}
+
+	// 4. Save raw output
+	sanitizedName := sanitizePath(workDir)
+	rawFile := filepath.Join(rawOutDir, fmt.Sprintf("yarn-%s.json", sanitizedName))
+	if err := os.WriteFile(rawFile, []byte(res.Stdout), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write raw output: %w", err)
+	}
+
+	// 5. Parse
+	findings, err := ParseYarnAudit(res.Stdout, lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+
+	return findings, nil
+}
+
+func sanitizePath(path string) string {
+	s := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, path)
+	return strings.Trim(s, "_")
+}